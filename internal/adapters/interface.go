@@ -16,6 +16,16 @@ type LanguageAdapter interface {
 	// ConvertType converts an OpenAPI schema to a language-specific type
 	ConvertType(schema *openapi.Schema) string
 
+	// JoinTypes combines types into a type accepting values of any of them
+	JoinTypes(types []string) string
+
+	// NoContentType returns the type of responses without content
+	NoContentType() string
+
+	// ConvertIndexSignatureType converts the additionalProperties of an object schema with fixed properties
+	// into the value type of its extra properties, or returns "" when extra properties are not declared
+	ConvertIndexSignatureType(schema *openapi.Schema) string
+
 	// FormatMethodName formats a method name according to language conventions
 	FormatMethodName(operationID, httpMethod string, tags []string) string
 
@@ -25,6 +35,23 @@ type LanguageAdapter interface {
 	// FormatPropertyName formats a property name according to language conventions
 	FormatPropertyName(name string) string
 
+	// FormatParameterName formats a parameter name into a safe identifier for method signatures
+	FormatParameterName(name string) string
+
+	// FormatEnumMemberName formats an enum value or vendor-provided name into an enum member name
+	FormatEnumMemberName(name string) string
+
+	// SetFormatMappings overrides the types generated for schema formats, e.g. "date-time" to "Date"
+	SetFormatMappings(mappings map[string]string)
+
+	// SetNullableRefs lists the component schemas allowing null whose declared types cannot include it,
+	// so references to them add null instead
+	SetNullableRefs(names []string)
+
+	// ConvertCodec returns the runtime codec converting values of a schema between their wire and
+	// generated representations, or an empty string when the values are used as they are
+	ConvertCodec(schema *openapi.Schema) string
+
 	// GetTemplateData prepares data for template rendering
 	GetTemplateData(model *models.ClientModel) interface{}
 
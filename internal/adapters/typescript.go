@@ -4,15 +4,117 @@ import (
 	"fmt"
 	"gogen/internal/models"
 	"gogen/internal/openapi"
+	"gogen/internal/utils"
+	"sort"
 	"strings"
+	"unicode"
 )
 
+// tsReservedWords are words that cannot be used as parameter names in generated methods, including the
+// locals and the module-level names used by the client template, which parameters would shadow
+var tsReservedWords = map[string]bool{
+	"break": true, "case": true, "catch": true, "class": true, "const": true, "continue": true,
+	"debugger": true, "default": true, "delete": true, "do": true, "else": true, "enum": true,
+	"export": true, "extends": true, "false": true, "finally": true, "for": true, "function": true,
+	"if": true, "import": true, "in": true, "instanceof": true, "new": true, "null": true,
+	"return": true, "super": true, "switch": true, "this": true, "throw": true, "true": true,
+	"try": true, "typeof": true, "var": true, "void": true, "while": true, "with": true,
+	"implements": true, "interface": true, "let": true, "package": true, "private": true,
+	"protected": true, "public": true, "static": true, "yield": true, "await": true,
+	"arguments": true, "eval": true,
+	"data": true, "config": true, "response": true, "pathParams": true, "query": true, "body": true,
+	"axios": true, "shapes": true, "securitySchemes": true, "oauth2Endpoints": true, "servers": true,
+	"operationServers": true, "encode": true, "decode": true, "isNode": true, "serializeBody": true, "serializePathParam": true,
+	"serializeQueryParams": true, "serializeHeaderParams": true, "composeCookieHeader": true,
+	"resolveSecurity": true, "createTokenProviders": true, "statusEntry": true, "toApiError": true,
+	"responseOptions": true, "toBlob": true, "toFileDownload": true, "parseResponseHeaders": true,
+	"parseJSONResponse": true, "resolveServerURL": true, "assertValid": true,
+}
+
+// tsFormatTypes are the default types of schema formats, formats without an entry use the type of the schema
+var tsFormatTypes = map[string]string{
+	"binary": "Blob",
+}
+
+// tsBuiltinTypes are the mapping targets that need no declaration, any other identifier becomes a branded type
+var tsBuiltinTypes = map[string]bool{
+	"string": true, "number": true, "bigint": true, "boolean": true, "any": true, "unknown": true,
+	"Date": true, "Blob": true, "File": true, "ArrayBuffer": true, "Uint8Array": true,
+}
+
 // TypeScriptAdapter implements LanguageAdapter for TypeScript
-type TypeScriptAdapter struct{}
+type TypeScriptAdapter struct {
+	formatTypes map[string]string
+	// brands maps the branded types used by the generated code to the types they brand
+	brands map[string]string
+	// nullableRefs marks the component schemas whose references add null, see SetNullableRefs
+	nullableRefs map[string]bool
+}
 
 // NewTypeScriptAdapter creates a new TypeScript adapter
 func NewTypeScriptAdapter() *TypeScriptAdapter {
-	return &TypeScriptAdapter{}
+	formatTypes := make(map[string]string, len(tsFormatTypes))
+	for format, tsType := range tsFormatTypes {
+		formatTypes[format] = tsType
+	}
+
+	return &TypeScriptAdapter{
+		formatTypes:  formatTypes,
+		brands:       make(map[string]string),
+		nullableRefs: make(map[string]bool),
+	}
+}
+
+// SetFormatMappings overrides the types of schema formats. Date revives date and date-time strings,
+// bigint and string keep int64 values exact, and other identifiers become branded types, e.g. "uuid": "Uuid".
+func (ts *TypeScriptAdapter) SetFormatMappings(mappings map[string]string) {
+	for format, tsType := range mappings {
+		ts.formatTypes[format] = tsType
+	}
+}
+
+// SetNullableRefs marks component schemas whose references add null, like nullable enums declared as
+// TypeScript enums, whose members cannot include null
+func (ts *TypeScriptAdapter) SetNullableRefs(names []string) {
+	for _, name := range names {
+		ts.nullableRefs[name] = true
+	}
+}
+
+// convertFormat returns the type mapped to the format of a primitive schema, if any
+func (ts *TypeScriptAdapter) convertFormat(schema *openapi.Schema) string {
+	tsType := ts.formatTypes[schema.Format]
+	if schema.Format == "" || tsType == "" || len(schema.Enum) > 0 {
+		return ""
+	}
+
+	if utils.IsIdentifier(tsType) && !tsBuiltinTypes[tsType] {
+		base := "string"
+		if schema.Type == "integer" || schema.Type == "number" {
+			base = "number"
+		}
+		ts.brands[tsType] = base
+	}
+
+	return tsType
+}
+
+// ConvertCodec returns the runtime codec of schemas whose format is mapped to a type other than their wire type
+func (ts *TypeScriptAdapter) ConvertCodec(schema *openapi.Schema) string {
+	if schema == nil || schema.Ref != "" {
+		return ""
+	}
+
+	tsType := ts.convertFormat(schema)
+	switch {
+	case tsType == "Date" && schema.Type == "string" && (schema.Format == "date" || schema.Format == "date-time"):
+		return schema.Format
+	case tsType == "bigint" && schema.Type == "integer":
+		return "bigint"
+	case tsType == "string" && schema.Type == "integer":
+		return "integer-string"
+	}
+	return ""
 }
 
 // GetFileExtension returns the file extension for TypeScript files
@@ -25,12 +127,32 @@ func (ts *TypeScriptAdapter) GetDependencies() []string {
 	return []string{"axios"}
 }
 
-// ConvertType converts an OpenAPI schema to a TypeScript type
+// JoinTypes combines types into a union type
+func (ts *TypeScriptAdapter) JoinTypes(types []string) string {
+	return strings.Join(types, " | ")
+}
+
+// NoContentType returns void, the type of responses without content
+func (ts *TypeScriptAdapter) NoContentType() string {
+	return "void"
+}
+
+// ConvertType converts an OpenAPI schema to a TypeScript type, adding null to the type of nullable schemas
 func (ts *TypeScriptAdapter) ConvertType(schema *openapi.Schema) string {
 	if schema == nil {
 		return "any"
 	}
 
+	tsType := ts.convertNonNullType(schema)
+	nullable := schema.Nullable || (schema.Ref != "" && ts.nullableRefs[strings.TrimPrefix(schema.Ref, "#/components/schemas/")])
+	if nullable && tsType != "null" && tsType != "any" {
+		return tsType + " | null"
+	}
+
+	return tsType
+}
+
+func (ts *TypeScriptAdapter) convertNonNullType(schema *openapi.Schema) string {
 	if schema.Ref != "" {
 		refName := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
 		return ts.FormatTypeName(refName)
@@ -48,47 +170,136 @@ func (ts *TypeScriptAdapter) ConvertType(schema *openapi.Schema) string {
 		return ts.handleAnyOf(schema)
 	}
 
+	if len(schema.Types) > 0 {
+		var types []string
+		for _, t := range schema.Types {
+			single := *schema
+			single.Type, single.Types, single.Nullable = t, nil, false
+			types = append(types, ts.convertNonNullType(&single))
+		}
+		return strings.Join(types, " | ")
+	}
+
+	if tsType := ts.convertFormat(schema); tsType != "" {
+		return tsType
+	}
+
 	switch schema.Type {
 	case "string":
 		if len(schema.Enum) > 0 {
-			var enumValues []string
-			for _, e := range schema.Enum {
-				enumValues = append(enumValues, fmt.Sprintf("'%v'", e))
-			}
-			return strings.Join(enumValues, " | ")
+			return enumLiterals(schema, utils.QuoteString)
 		}
 		return "string"
 	case "integer", "number":
+		if len(schema.Enum) > 0 {
+			return enumLiterals(schema, func(s string) string { return s })
+		}
 		return "number"
 	case "boolean":
 		return "boolean"
+	case "null":
+		return "null"
 	case "array":
 		if schema.Items == nil {
 			return "any[]"
 		}
 
-		if schema.Items.Ref != "" {
-			refName := strings.TrimPrefix(schema.Items.Ref, "#/components/schemas/")
-			return ts.FormatTypeName(refName) + "[]"
-		}
-
 		itemType := ts.ConvertType(schema.Items)
+		if strings.ContainsAny(itemType, "|&") {
+			itemType = "(" + itemType + ")"
+		}
 		return itemType + "[]"
 	case "object":
-		if schema.Properties == nil {
-			return "Record<string, any>"
+		return ts.convertObject(schema)
+	case "":
+		if schema.Properties != nil || schema.AdditionalProperties != nil {
+			return ts.convertObject(schema)
 		}
+		return "any"
+	default:
+		return "any"
+	}
+}
 
-		var properties []string
-		for propName, propSchema := range schema.Properties {
-			propType := ts.ConvertType(propSchema)
-			properties = append(properties, fmt.Sprintf("%s: %s", propName, propType))
+func (ts *TypeScriptAdapter) convertObject(schema *openapi.Schema) string {
+	if len(schema.Properties) == 0 {
+		if !schema.AdditionalProperties.Allowed() {
+			return "Record<string, never>"
 		}
+		// an index signature rather than Record, as only the former may refer to the type being declared
+		if schema.AdditionalProperties != nil {
+			return "{ [key: string]: " + ts.ConvertType(schema.AdditionalProperties.SchemaValue) + " }"
+		}
+		return "Record<string, any>"
+	}
 
-		return "{" + strings.Join(properties, ", ") + "}"
-	default:
-		return "any"
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var properties []string
+	for _, name := range names {
+		propSchema := schema.Properties[name]
+
+		optional := "?"
+		if schema.IsRequired(name) {
+			optional = ""
+		}
+
+		property := fmt.Sprintf("%s%s: %s", ts.FormatPropertyName(name), optional, ts.ConvertType(propSchema))
+		if propSchema != nil && propSchema.ReadOnly {
+			property = "readonly " + property
+		}
+		if propSchema != nil && propSchema.Description != "" {
+			property = "/** " + utils.InlineComment(propSchema.Description) + " */ " + property
+		}
+		properties = append(properties, property)
+	}
+
+	if indexType := ts.ConvertIndexSignatureType(schema); indexType != "" {
+		properties = append(properties, "[key: string]: "+indexType)
+	}
+
+	return "{ " + strings.Join(properties, "; ") + " }"
+}
+
+// ConvertIndexSignatureType converts additionalProperties into an index signature type. TypeScript requires
+// every fixed property to fit the index signature, so the type is widened with the property types.
+func (ts *TypeScriptAdapter) ConvertIndexSignatureType(schema *openapi.Schema) string {
+	if schema == nil || schema.AdditionalProperties == nil || !schema.AdditionalProperties.Allowed() {
+		return ""
 	}
+
+	valueType := ts.ConvertType(schema.AdditionalProperties.SchemaValue)
+	if valueType == "any" || valueType == "unknown" {
+		return valueType
+	}
+
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	types := []string{valueType}
+	optional := false
+	for _, name := range names {
+		propType := ts.ConvertType(schema.Properties[name])
+		if !utils.Contains(types, propType) {
+			types = append(types, propType)
+		}
+		if !schema.IsRequired(name) {
+			optional = true
+		}
+	}
+
+	if optional {
+		types = append(types, "undefined")
+	}
+
+	return strings.Join(types, " | ")
 }
 
 // FormatMethodName formats a method name using camelCase convention
@@ -118,31 +329,133 @@ func (ts *TypeScriptAdapter) FormatTypeName(name string) string {
 	return name
 }
 
-// FormatPropertyName formats a property name (no change for TypeScript)
+// FormatPropertyName keeps property names as they are, quoting those that are not valid identifiers
 func (ts *TypeScriptAdapter) FormatPropertyName(name string) string {
-	return name
+	if utils.IsIdentifier(name) {
+		return name
+	}
+	return utils.QuoteString(name)
+}
+
+// FormatEnumMemberName converts an enum value into a PascalCase member name
+func (ts *TypeScriptAdapter) FormatEnumMemberName(name string) string {
+	if utils.IsIdentifier(name) && strings.ToUpper(name[:1]) == name[:1] {
+		return name
+	}
+
+	member := utils.ToPascalCase(name)
+	if member == "" {
+		return "Empty"
+	}
+	if unicode.IsDigit([]rune(member)[0]) {
+		member = "Value" + member
+	}
+	return utils.ToPascalIdentifier(member)
+}
+
+// enumLiterals joins the values of an enum into a union of literals. A null value is left
+// to ConvertType when the schema is nullable, so it is not listed twice.
+func enumLiterals(schema *openapi.Schema, literal func(string) string) string {
+	var enumValues []string
+	for _, e := range schema.Enum {
+		if e == nil {
+			if !schema.Nullable {
+				enumValues = append(enumValues, "null")
+			}
+			continue
+		}
+		enumValues = append(enumValues, literal(fmt.Sprint(e)))
+	}
+	return strings.Join(enumValues, " | ")
+}
+
+// FormatParameterName converts a wire parameter name into a valid, non-reserved TypeScript identifier
+func (ts *TypeScriptAdapter) FormatParameterName(name string) string {
+	identifier := name
+	if !utils.IsIdentifier(identifier) {
+		identifier = utils.ToIdentifier(identifier)
+	}
+
+	if tsReservedWords[identifier] {
+		identifier += "Param"
+	}
+
+	return identifier
 }
 
 // GetTemplateData prepares data for TypeScript template rendering
 func (ts *TypeScriptAdapter) GetTemplateData(model *models.ClientModel) interface{} {
+	// component types of the same name take precedence over brands
+	var brands []models.TypeModel
+	for name, base := range ts.brands {
+		declared := false
+		for _, t := range model.Types {
+			declared = declared || t.Name == name
+		}
+		if !declared {
+			brands = append(brands, models.TypeModel{Name: name, Type: base})
+		}
+	}
+	sort.Slice(brands, func(i, j int) bool { return brands[i].Name < brands[j].Name })
+
 	return struct {
 		*models.ClientModel
 		ClientClassName string
+		BrandedTypes    []models.TypeModel
+		// LosslessIntegers parses responses keeping integers beyond the safe range exact, for the integer codecs
+		LosslessIntegers bool
 	}{
-		ClientModel:     model,
-		ClientClassName: model.ProjectName + "Client",
+		ClientModel:      model,
+		ClientClassName:  model.ProjectName + "Client",
+		BrandedTypes:     brands,
+		LosslessIntegers: utils.Contains(model.Codecs, "bigint") || utils.Contains(model.Codecs, "integer-string"),
 	}
 }
 
 func (ts *TypeScriptAdapter) handleAllOf(schema *openapi.Schema) string {
 	var types []string
 	for _, subSchema := range schema.AllOf {
-		types = append(types, ts.ConvertType(&subSchema))
+		memberType := ts.ConvertType(&subSchema)
+		// unions bind looser than intersections, e.g. nullable members rendered as "A | null"
+		if hasTopLevelUnion(memberType) {
+			memberType = "(" + memberType + ")"
+		}
+		types = append(types, memberType)
 	}
 
 	return strings.Join(types, " & ")
 }
 
+// hasTopLevelUnion checks if a type contains a "|" outside of brackets and string literals
+func hasTopLevelUnion(tsType string) bool {
+	depth := 0
+	var quote, previous rune
+	escaped := false
+	for _, r := range tsType {
+		arrow := r == '>' && previous == '='
+		previous = r
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0:
+			if r == '\\' {
+				escaped = true
+			} else if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '(' || r == '[' || r == '{' || r == '<':
+			depth++
+		case r == ')' || r == ']' || r == '}' || (r == '>' && !arrow):
+			depth--
+		case r == '|' && depth == 0:
+			return true
+		}
+	}
+	return false
+}
+
 func (ts *TypeScriptAdapter) handleOneOf(schema *openapi.Schema) string {
 	var types []string
 	for _, subSchema := range schema.OneOf {
@@ -161,6 +474,7 @@ func (ts *TypeScriptAdapter) handleAnyOf(schema *openapi.Schema) string {
 	return strings.Join(types, " | ")
 }
 
+// FormatPath turns path placeholders into references to the serialized path parameters of the client template
 func (ts *TypeScriptAdapter) FormatPath(path, httpMethod string) string {
-	return strings.ReplaceAll(path, "{", "${")
+	return strings.ReplaceAll(path, "{", "${pathParams.")
 }
@@ -0,0 +1,68 @@
+package adapters
+
+import (
+	"encoding/json"
+	"gogen/internal/openapi"
+	"testing"
+)
+
+// TestConvertRecursiveTypes checks that references to schemas are kept as type names wherever a schema may
+// refer to itself, so the types of recursive schemas can be declared
+func TestConvertRecursiveTypes(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		want   string
+	}{
+		{
+			name:   "self reference",
+			schema: `{"$ref":"#/components/schemas/Node"}`,
+			want:   "Node",
+		},
+		{
+			name:   "nullable self reference",
+			schema: `{"$ref":"#/components/schemas/Node","nullable":true}`,
+			want:   "Node | null",
+		},
+		{
+			name:   "array of references",
+			schema: `{"type":"array","items":{"$ref":"#/components/schemas/Tree"}}`,
+			want:   "Tree[]",
+		},
+		{
+			name:   "map of references",
+			schema: `{"type":"object","additionalProperties":{"$ref":"#/components/schemas/Json"}}`,
+			want:   "{ [key: string]: Json }",
+		},
+		{
+			name: "union through arrays and maps",
+			schema: `{"oneOf":[{"type":"string"},{"type":"array","items":{"$ref":"#/components/schemas/Json"}},
+				{"type":"object","additionalProperties":{"$ref":"#/components/schemas/Json"}}]}`,
+			want: "string | Json[] | { [key: string]: Json }",
+		},
+		{
+			name:   "array of a union of references",
+			schema: `{"type":"array","items":{"oneOf":[{"$ref":"#/components/schemas/Folder"},{"$ref":"#/components/schemas/File"}]}}`,
+			want:   "(Folder | File)[]",
+		},
+		{
+			name:   "inline object referring to a component",
+			schema: `{"type":"object","properties":{"comment":{"$ref":"#/components/schemas/Comment"}}}`,
+			want:   "{ comment?: Comment }",
+		},
+	}
+
+	adapter := NewTypeScriptAdapter()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var schema openapi.Schema
+			if err := json.Unmarshal([]byte(test.schema), &schema); err != nil {
+				t.Fatalf("failed to parse schema: %v", err)
+			}
+
+			if got := adapter.ConvertType(&schema); got != test.want {
+				t.Errorf("ConvertType() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package builder
+
+import (
+	"gogen/internal/models"
+	"gogen/internal/openapi"
+	"sort"
+)
+
+// objectProperties collects the properties and required property names of a plain object schema, merging
+// its allOf members and the schemas they reference. It reports false for schemas that are not plain objects,
+// like unions, primitives, maps or nullable objects, which cannot be merged into a single object type.
+func (g *ClientGenerator) objectProperties(schema *openapi.Schema, visiting map[string]bool) (map[string]*openapi.Schema, map[string]bool, bool) {
+	if schema.Ref != "" {
+		name := refName(schema.Ref)
+		component, exists := g.spec.Components.Schemas[name]
+		if !exists || visiting[name] || (len(component.Properties) == 0 && len(component.AllOf) == 0) {
+			return nil, nil, false
+		}
+
+		visiting[name] = true
+		defer delete(visiting, name)
+		return g.objectProperties(&component, visiting)
+	}
+
+	if schema.Nullable || (schema.Type != "" && schema.Type != "object") || len(schema.Types) > 0 ||
+		len(schema.OneOf) > 0 || len(schema.AnyOf) > 0 || len(schema.Enum) > 0 || schema.Items != nil ||
+		(schema.AdditionalProperties != nil && schema.AdditionalProperties.Allowed()) {
+		return nil, nil, false
+	}
+
+	properties := make(map[string]*openapi.Schema)
+	required := make(map[string]bool)
+
+	for i := range schema.AllOf {
+		memberProperties, memberRequired, ok := g.objectProperties(&schema.AllOf[i], visiting)
+		if !ok {
+			return nil, nil, false
+		}
+		for name, property := range memberProperties {
+			properties[name] = property
+		}
+		for name := range memberRequired {
+			required[name] = true
+		}
+	}
+
+	for name, property := range schema.Properties {
+		properties[name] = property
+		if schema.IsRequired(name) {
+			required[name] = true
+		}
+	}
+	if schema.Required != nil {
+		for _, name := range schema.Required.ArrayValue {
+			required[name] = true
+		}
+	}
+
+	return properties, required, true
+}
+
+// processAllOfSchema builds an object type from an allOf composition of objects. The type extends the
+// referenced schemas and declares the properties of the inline members; when an inline member redefines a
+// property of a referenced schema, all properties are merged into the type instead. Compositions of
+// anything but objects return nil and stay intersections.
+func (g *ClientGenerator) processAllOfSchema(name string, schema *openapi.Schema) *models.TypeModel {
+	properties, required, ok := g.objectProperties(schema, map[string]bool{name: true})
+	if !ok || len(properties) == 0 || (len(schema.AllOf) == 1 && schema.AllOf[0].Ref != "" && len(schema.Properties) == 0) {
+		return nil
+	}
+
+	var bases []string
+	baseProperties := make(map[string]*openapi.Schema)
+	baseRequired := make(map[string]bool)
+	own := make(map[string]bool)
+
+	for i := range schema.AllOf {
+		member := &schema.AllOf[i]
+		if member.Ref == "" {
+			for propName := range member.Properties {
+				own[propName] = true
+			}
+			continue
+		}
+
+		bases = append(bases, g.adapter.FormatTypeName(refName(member.Ref)))
+		memberProperties, memberRequired, _ := g.objectProperties(member, map[string]bool{name: true})
+		for propName, property := range memberProperties {
+			baseProperties[propName] = property
+		}
+		for propName := range memberRequired {
+			baseRequired[propName] = true
+		}
+	}
+	for propName := range schema.Properties {
+		own[propName] = true
+	}
+
+	extendable := true
+	for propName := range own {
+		if _, inherited := baseProperties[propName]; inherited {
+			extendable = false
+		}
+	}
+
+	requiredNames := make([]string, 0, len(required))
+	for propName := range required {
+		requiredNames = append(requiredNames, propName)
+	}
+	sort.Strings(requiredNames)
+
+	merged := &openapi.Schema{
+		Type:       "object",
+		Properties: properties,
+		Required:   &openapi.FlexibleRequired{ArrayValue: requiredNames},
+	}
+	typeModel := g.processObjectSchema(name, merged)
+	typeModel.Doc = buildDoc(schema)
+
+	if !extendable {
+		return typeModel
+	}
+
+	// inherited properties are only declared again when they become required or narrowed here
+	declared := make(map[string]bool)
+	for propName := range properties {
+		_, inherited := baseProperties[propName]
+		_, narrowed := g.discriminatorValues[name][propName]
+		if !inherited || narrowed || (required[propName] && !baseRequired[propName]) {
+			declared[g.adapter.FormatPropertyName(propName)] = true
+		}
+	}
+
+	var ownProperties []models.PropertyModel
+	for _, property := range typeModel.Properties {
+		if declared[property.Name] {
+			ownProperties = append(ownProperties, property)
+		}
+	}
+
+	typeModel.Properties = ownProperties
+	typeModel.Extends = bases
+	return typeModel
+}
@@ -0,0 +1,199 @@
+package builder
+
+import (
+	"encoding/json"
+	"gogen/internal/adapters"
+	"gogen/internal/models"
+	"gogen/internal/openapi"
+	"sort"
+)
+
+// codecShape describes where the values of a schema need converting between their wire and generated
+// representations, e.g. date-time strings revived as Date objects. It is rendered into the generated
+// code as JSON, with plain codec names for converted values.
+type codecShape struct {
+	Codec         string                 `json:"-"`
+	Ref           string                 `json:"ref,omitempty"`
+	Items         *codecShape            `json:"items,omitempty"`
+	Properties    map[string]*codecShape `json:"properties,omitempty"`
+	Values        *codecShape            `json:"values,omitempty"`
+	All           []*codecShape          `json:"all,omitempty"`
+	Discriminator string                 `json:"discriminator,omitempty"`
+	Mapping       map[string]*codecShape `json:"mapping,omitempty"`
+}
+
+func (s *codecShape) MarshalJSON() ([]byte, error) {
+	if s.Codec != "" {
+		return json.Marshal(s.Codec)
+	}
+
+	type shapeAlias codecShape
+	return json.Marshal((*shapeAlias)(s))
+}
+
+// codecBuilder builds the codec shapes of schemas, referencing the shapes of component schemas by name
+type codecBuilder struct {
+	adapter adapters.LanguageAdapter
+	// needed marks the component schemas that contain converted values, directly or through references
+	needed map[string]bool
+	codecs map[string]bool
+}
+
+// buildCodecs finds the component schemas with values needing conversion and builds their shapes
+func (g *ClientGenerator) buildCodecs() {
+	g.codecs = &codecBuilder{
+		adapter: g.adapter,
+		needed:  make(map[string]bool),
+		codecs:  make(map[string]bool),
+	}
+
+	// a schema needs a shape when it contains a codec or references a schema that does; repeat until
+	// nothing changes so references in any order, including cyclic ones, are settled
+	for changed := true; changed; {
+		changed = false
+		for name, schema := range g.spec.Components.Schemas {
+			if !g.codecs.needed[name] && g.codecs.needsShape(&schema) {
+				g.codecs.needed[name] = true
+				changed = true
+			}
+		}
+	}
+}
+
+func (c *codecBuilder) needsShape(schema *openapi.Schema) bool {
+	needed := false
+	walkSchema(schema, func(s *openapi.Schema) {
+		if (s.Ref != "" && c.needed[refName(s.Ref)]) || c.adapter.ConvertCodec(s) != "" {
+			needed = true
+		}
+	})
+	return needed
+}
+
+// shape returns the codec shape of a schema, or nil when none of its values need conversion
+func (c *codecBuilder) shape(schema *openapi.Schema) *codecShape {
+	if schema == nil {
+		return nil
+	}
+
+	if schema.Ref != "" {
+		if name := refName(schema.Ref); c.needed[name] {
+			return &codecShape{Ref: name}
+		}
+		return nil
+	}
+
+	if codec := c.adapter.ConvertCodec(schema); codec != "" {
+		c.codecs[codec] = true
+		return &codecShape{Codec: codec}
+	}
+
+	if len(schema.AllOf) > 0 {
+		var all []*codecShape
+		for i := range schema.AllOf {
+			if shape := c.shape(&schema.AllOf[i]); shape != nil {
+				all = append(all, shape)
+			}
+		}
+		if len(all) == 1 {
+			return all[0]
+		}
+		if len(all) > 1 {
+			return &codecShape{All: all}
+		}
+		return nil
+	}
+
+	// only discriminated unions tell which variant, and so which conversions, apply to a value
+	if len(schema.OneOf) > 0 || len(schema.AnyOf) > 0 {
+		if schema.Discriminator == nil || schema.Discriminator.PropertyName == "" {
+			return nil
+		}
+
+		mapping := make(map[string]*codecShape)
+		for _, variant := range discriminatorVariants(schema) {
+			if !c.needed[variant.schemaName] {
+				continue
+			}
+			for _, value := range variant.values {
+				mapping[value] = &codecShape{Ref: variant.schemaName}
+			}
+		}
+		if len(mapping) == 0 {
+			return nil
+		}
+		return &codecShape{Discriminator: schema.Discriminator.PropertyName, Mapping: mapping}
+	}
+
+	if schema.Items != nil {
+		if items := c.shape(schema.Items); items != nil {
+			return &codecShape{Items: items}
+		}
+		return nil
+	}
+
+	shape := &codecShape{}
+	for name, property := range schema.Properties {
+		if propertyShape := c.shape(property); propertyShape != nil {
+			if shape.Properties == nil {
+				shape.Properties = make(map[string]*codecShape)
+			}
+			shape.Properties[name] = propertyShape
+		}
+	}
+	if schema.AdditionalProperties != nil {
+		shape.Values = c.shape(schema.AdditionalProperties.SchemaValue)
+	}
+
+	// fixed properties take an empty shape so the shape of extra properties is not applied to them
+	if shape.Values != nil {
+		for name := range schema.Properties {
+			if shape.Properties == nil {
+				shape.Properties = make(map[string]*codecShape)
+			}
+			if shape.Properties[name] == nil {
+				shape.Properties[name] = &codecShape{}
+			}
+		}
+	}
+
+	if shape.Properties == nil && shape.Values == nil {
+		return nil
+	}
+	return shape
+}
+
+// render renders the codec shape of a schema, or an empty string when none of its values need conversion
+func (c *codecBuilder) render(schema *openapi.Schema) string {
+	shape := c.shape(schema)
+	if shape == nil {
+		return ""
+	}
+
+	data, err := json.Marshal(shape)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// buildShapes renders the shapes of the component schemas that need one, and lists the codecs in use
+func (g *ClientGenerator) buildShapes(model *models.ClientModel) {
+	names := make([]string, 0, len(g.codecs.needed))
+	for name := range g.codecs.needed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		schema := g.spec.Components.Schemas[name]
+		if shape := g.codecs.render(&schema); shape != "" {
+			model.Shapes = append(model.Shapes, models.ShapeModel{Name: name, Shape: shape})
+		}
+	}
+
+	for codec := range g.codecs.codecs {
+		model.Codecs = append(model.Codecs, codec)
+	}
+	sort.Strings(model.Codecs)
+}
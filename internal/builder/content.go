@@ -0,0 +1,193 @@
+package builder
+
+import (
+	"encoding/json"
+	"gogen/internal/models"
+	"gogen/internal/openapi"
+	"sort"
+	"strings"
+)
+
+// partEncoding is the language-neutral form of the encoding of a multipart or form body property
+type partEncoding struct {
+	ContentType   string `json:"contentType,omitempty"`
+	Style         string `json:"style,omitempty"`
+	Explode       *bool  `json:"explode,omitempty"`
+	AllowReserved bool   `json:"allowReserved,omitempty"`
+}
+
+// mediaTypeName returns a content type without its parameters, e.g. "text/plain" for "text/plain; charset=utf-8"
+func mediaTypeName(contentType string) string {
+	return strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+}
+
+func isJSONMediaType(contentType string) bool {
+	name := mediaTypeName(contentType)
+	return name == "application/json" || strings.HasSuffix(name, "+json")
+}
+
+func isFormMediaType(contentType string) bool {
+	name := mediaTypeName(contentType)
+	return name == "multipart/form-data" || name == "application/x-www-form-urlencoded"
+}
+
+// contentTypeRank orders request content types by preference: JSON first, then multipart and url-encoded
+// forms, then anything else
+func contentTypeRank(contentType string) int {
+	switch name := mediaTypeName(contentType); {
+	case name == "application/json":
+		return 0
+	case isJSONMediaType(name):
+		return 1
+	case name == "multipart/form-data":
+		return 2
+	case name == "application/x-www-form-urlencoded":
+		return 3
+	default:
+		return 4
+	}
+}
+
+// sortedContentTypes returns the content types of a content map, preferred ones first
+func sortedContentTypes(content map[string]openapi.MediaType) []string {
+	contentTypes := make([]string, 0, len(content))
+	for contentType := range content {
+		contentTypes = append(contentTypes, contentType)
+	}
+	sort.Slice(contentTypes, func(i, j int) bool {
+		if rank, other := contentTypeRank(contentTypes[i]), contentTypeRank(contentTypes[j]); rank != other {
+			return rank < other
+		}
+		return contentTypes[i] < contentTypes[j]
+	})
+	return contentTypes
+}
+
+// contentSchema returns the schema of a media type. Media types without a schema hold text for text
+// content types and binary data for anything but JSON or forms.
+func contentSchema(contentType string, mediaType openapi.MediaType) *openapi.Schema {
+	if mediaType.Schema != nil || isJSONMediaType(contentType) || isFormMediaType(contentType) {
+		return mediaType.Schema
+	}
+	if strings.HasPrefix(mediaTypeName(contentType), "text/") {
+		return &openapi.Schema{Type: "string"}
+	}
+	return &openapi.Schema{Type: "string", Format: "binary"}
+}
+
+// renderEncoding renders the encoding of the properties of a multipart or form body, or an empty string
+// when the defaults apply to all of them
+func renderEncoding(encoding map[string]openapi.Encoding) string {
+	if len(encoding) == 0 {
+		return ""
+	}
+
+	parts := make(map[string]partEncoding, len(encoding))
+	for name, part := range encoding {
+		parts[name] = partEncoding{
+			ContentType:   part.ContentType,
+			Style:         part.Style,
+			Explode:       part.Explode,
+			AllowReserved: part.AllowReserved,
+		}
+	}
+
+	data, err := json.Marshal(parts)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// buildRequestBody models every content type of a request body. The body is typed as any of the declared
+// schemas; callers pick the content type to send when there are several. Names holds the parameter names
+// of the method, which the content type parameter must not collide with.
+func (g *ClientGenerator) buildRequestBody(requestBody *openapi.RequestBody, names map[string]bool) *models.RequestBodyModel {
+	model := &models.RequestBodyModel{Required: requestBody.Required}
+
+	var types []string
+	var schemas []openapi.Schema
+	seen := make(map[string]bool)
+
+	for _, contentType := range sortedContentTypes(requestBody.Content) {
+		mediaType := requestBody.Content[contentType]
+		schema := g.inputSchema(contentSchema(contentType, mediaType))
+
+		content := models.RequestContentModel{
+			ContentType: contentType,
+			Type:        g.adapter.ConvertType(schema),
+			Shape:       g.codecs.render(schema),
+		}
+		if isFormMediaType(contentType) {
+			content.Encoding = renderEncoding(mediaType.Encoding)
+		}
+		model.Contents = append(model.Contents, content)
+
+		if !seen[content.Type] {
+			seen[content.Type] = true
+			types = append(types, content.Type)
+			if schema != nil {
+				schemas = append(schemas, *schema)
+			}
+		}
+	}
+
+	model.ContentType = model.Contents[0].ContentType
+	model.Type = g.adapter.JoinTypes(types)
+
+	if len(model.Contents) > 1 {
+		model.ContentTypeParam = g.adapter.FormatParameterName("contentType")
+		if names[model.ContentTypeParam] {
+			model.ContentTypeParam = g.adapter.FormatParameterName("requestContentType")
+		}
+	}
+
+	// the body is validated against any of the declared schemas, unless one of them accepts anything
+	if g.validation && len(schemas) == len(types) {
+		if len(schemas) == 1 {
+			model.Rule = g.renderRule(&schemas[0])
+		} else {
+			model.Rule = g.renderRule(&openapi.Schema{AnyOf: schemas})
+		}
+	}
+
+	return model
+}
+
+// isBinarySchema checks if a schema describes raw bytes rather than a JSON value
+func isBinarySchema(schema *openapi.Schema) bool {
+	return schema != nil && schema.Type == "string" && schema.Format == "binary"
+}
+
+// responseFormat tells how a response of the given content type is read: JSON, text or binary data.
+// Wildcard content types, common in generated specs, are read as JSON unless their schema is binary.
+func responseFormat(contentType string, schema *openapi.Schema) string {
+	name := mediaTypeName(contentType)
+	switch {
+	case name == "":
+		return ""
+	case isJSONMediaType(name) || (strings.Contains(name, "*") && !isBinarySchema(schema)):
+		return "json"
+	case strings.HasPrefix(name, "text/") || name == "application/xml" || strings.HasSuffix(name, "+xml") ||
+		name == "application/x-www-form-urlencoded":
+		return "text"
+	default:
+		return "binary"
+	}
+}
+
+// responseContent returns the preferred content type of a response and its schema, without its write-only
+// properties. Text and binary content is read as it is, whatever its schema says.
+func (g *ClientGenerator) responseContent(response openapi.Response) (string, *openapi.Schema) {
+	for _, contentType := range sortedContentTypes(response.Content) {
+		schema := response.Content[contentType].Schema
+		switch responseFormat(contentType, schema) {
+		case "text":
+			schema = &openapi.Schema{Type: "string"}
+		case "binary":
+			schema = &openapi.Schema{Type: "string", Format: "binary"}
+		}
+		return contentType, g.outputSchema(schema)
+	}
+	return "", nil
+}
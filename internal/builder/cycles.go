@@ -0,0 +1,141 @@
+package builder
+
+import (
+	"gogen/internal/openapi"
+	"gogen/internal/utils"
+	"log"
+	"sort"
+)
+
+// schemaGraph maps every component schema to the component schemas it references
+func schemaGraph(schemas map[string]openapi.Schema, refs func(*openapi.Schema) []string) map[string][]string {
+	graph := make(map[string][]string, len(schemas))
+	for name, schema := range schemas {
+		for _, ref := range refs(&schema) {
+			if hasSchema(schemas, ref) && !utils.Contains(graph[name], ref) {
+				graph[name] = append(graph[name], ref)
+			}
+		}
+		sort.Strings(graph[name])
+	}
+	return graph
+}
+
+// findCycles returns the groups of schemas that reference each other, directly or through other schemas,
+// using Tarjan's strongly connected components algorithm. Groups and their members are sorted.
+func findCycles(graph map[string][]string) [][]string {
+	names := make([]string, 0, len(graph))
+	for name := range graph {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	index := make(map[string]int)
+	lowLink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var cycles [][]string
+
+	var connect func(name string)
+	connect = func(name string) {
+		index[name] = len(index)
+		lowLink[name] = index[name]
+		stack = append(stack, name)
+		onStack[name] = true
+
+		for _, ref := range graph[name] {
+			if _, visited := index[ref]; !visited {
+				connect(ref)
+				lowLink[name] = min(lowLink[name], lowLink[ref])
+			} else if onStack[ref] {
+				lowLink[name] = min(lowLink[name], index[ref])
+			}
+		}
+
+		if lowLink[name] != index[name] {
+			return
+		}
+
+		var component []string
+		for {
+			member := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[member] = false
+			component = append(component, member)
+			if member == name {
+				break
+			}
+		}
+
+		if len(component) > 1 || utils.Contains(graph[name], name) {
+			sort.Strings(component)
+			cycles = append(cycles, component)
+		}
+	}
+
+	for _, name := range names {
+		if _, visited := index[name]; !visited {
+			connect(name)
+		}
+	}
+
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i][0] < cycles[j][0] })
+	return cycles
+}
+
+// eagerRefs lists the component schemas a schema stands for directly: its own reference and those of its
+// allOf, oneOf and anyOf members. Unlike references from properties, items or map values, a cycle of these
+// has no value in between and cannot be declared as a type.
+func eagerRefs(schema *openapi.Schema) []string {
+	var refs []string
+	if schema.Ref != "" {
+		refs = append(refs, refName(schema.Ref))
+	}
+	for _, members := range [][]openapi.Schema{schema.AllOf, schema.OneOf, schema.AnyOf} {
+		for i := range members {
+			refs = append(refs, eagerRefs(&members[i])...)
+		}
+	}
+	return refs
+}
+
+// dropEagerRefs removes the references to the given schemas from the positions listed by eagerRefs
+func dropEagerRefs(schema *openapi.Schema, targets []string) {
+	if schema.Ref != "" && utils.Contains(targets, refName(schema.Ref)) {
+		schema.Ref = ""
+	}
+
+	for _, members := range []*[]openapi.Schema{&schema.AllOf, &schema.OneOf, &schema.AnyOf} {
+		var kept []openapi.Schema
+		for _, member := range *members {
+			if member.Ref != "" && utils.Contains(targets, refName(member.Ref)) {
+				continue
+			}
+			dropEagerRefs(&member, targets)
+			kept = append(kept, member)
+		}
+		*members = kept
+	}
+}
+
+// detectCycles breaks up the reference cycles without an object or array in between, like a schema in its
+// own allOf, by dropping the references of one schema, as they describe no finite value. Other cycles need
+// nothing more: object types are interfaces and maps use index signatures, which may refer to themselves.
+func (g *ClientGenerator) detectCycles() {
+	schemas := g.spec.Components.Schemas
+
+	for {
+		cycles := findCycles(schemaGraph(schemas, eagerRefs))
+		if len(cycles) == 0 {
+			break
+		}
+
+		name := cycles[0][0]
+		component := schemas[name]
+		schema := cloneSchema(&component)
+		dropEagerRefs(schema, cycles[0])
+		schemas[name] = *schema
+
+		log.Printf("Warning: schema %s is defined in terms of itself through %v, dropping these references", name, cycles[0])
+	}
+}
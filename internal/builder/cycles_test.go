@@ -0,0 +1,222 @@
+package builder
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// newTestGenerator creates a TypeScript generator for a spec given as JSON
+func newTestGenerator(t *testing.T, spec string) *ClientGenerator {
+	t.Helper()
+
+	specPath := filepath.Join(t.TempDir(), "spec.json")
+	if err := os.WriteFile(specPath, []byte(spec), 0644); err != nil {
+		t.Fatalf("failed to write spec: %v", err)
+	}
+
+	return NewClientGeneratorBuilder().
+		WithSpec(specPath).
+		WithProjectName("Test").
+		WithOutputDir(t.TempDir()).
+		WithLanguage("typescript").
+		Build()
+}
+
+// componentsSpec wraps component schemas given as JSON in a spec without paths
+func componentsSpec(schemas string) string {
+	return `{"openapi":"3.0.0","info":{"title":"Test","version":"1"},"paths":{},"components":{"schemas":{` + schemas + `}}}`
+}
+
+func TestFindCycles(t *testing.T) {
+	tests := []struct {
+		name  string
+		graph map[string][]string
+		want  [][]string
+	}{
+		{
+			name:  "no references",
+			graph: map[string][]string{"A": nil, "B": nil},
+			want:  nil,
+		},
+		{
+			name:  "chain without cycle",
+			graph: map[string][]string{"A": {"B"}, "B": {"C"}, "C": nil},
+			want:  nil,
+		},
+		{
+			name:  "self reference",
+			graph: map[string][]string{"Node": {"Node"}, "Leaf": nil},
+			want:  [][]string{{"Node"}},
+		},
+		{
+			name:  "mutual recursion",
+			graph: map[string][]string{"A": {"B"}, "B": {"A"}},
+			want:  [][]string{{"A", "B"}},
+		},
+		{
+			name:  "cycle through three schemas with a tail",
+			graph: map[string][]string{"A": {"B"}, "B": {"C"}, "C": {"A"}, "D": {"A"}},
+			want:  [][]string{{"A", "B", "C"}},
+		},
+		{
+			name:  "separate cycles",
+			graph: map[string][]string{"X": {"Y"}, "Y": {"X"}, "A": {"A"}},
+			want:  [][]string{{"A"}, {"X", "Y"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := findCycles(test.graph); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("findCycles() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDetectCycles(t *testing.T) {
+	tests := []struct {
+		name    string
+		schemas string
+		// recursive are the schemas expected to be left in a reference cycle
+		recursive []string
+		// dropped are the schemas whose eager references to themselves are expected to be dropped
+		dropped []string
+	}{
+		{
+			name:      "self reference",
+			schemas:   `"Node":{"type":"object","properties":{"next":{"$ref":"#/components/schemas/Node"}}}`,
+			recursive: []string{"Node"},
+		},
+		{
+			name: "mutual recursion",
+			schemas: `"Person":{"type":"object","properties":{"employer":{"$ref":"#/components/schemas/Company"}}},
+				"Company":{"type":"object","properties":{"owner":{"$ref":"#/components/schemas/Person"}}}`,
+			recursive: []string{"Company", "Person"},
+		},
+		{
+			name: "recursion through a hoisted inline schema",
+			schemas: `"Comment":{"type":"object","properties":{"replies":{"type":"array","items":{"type":"object",
+				"properties":{"comment":{"$ref":"#/components/schemas/Comment"}}}}}}`,
+			recursive: []string{"Comment", "CommentReplies"},
+		},
+		{
+			name:      "recursion through an array",
+			schemas:   `"Tree":{"type":"object","properties":{"children":{"type":"array","items":{"$ref":"#/components/schemas/Tree"}}}}`,
+			recursive: []string{"Tree"},
+		},
+		{
+			name:      "recursion through a map",
+			schemas:   `"Json":{"oneOf":[{"type":"string"},{"type":"object","additionalProperties":{"$ref":"#/components/schemas/Json"}}]}`,
+			recursive: []string{"Json"},
+		},
+		{
+			name:    "eager allOf self cycle",
+			schemas: `"Loop":{"allOf":[{"$ref":"#/components/schemas/Loop"},{"type":"object","properties":{"x":{"type":"string"}}}]}`,
+			dropped: []string{"Loop"},
+		},
+		{
+			name: "eager cycle between references and unions",
+			schemas: `"A":{"$ref":"#/components/schemas/B"},
+				"B":{"oneOf":[{"$ref":"#/components/schemas/A"},{"type":"string"}]}`,
+			dropped: []string{"A"},
+		},
+		{
+			name:    "no cycle",
+			schemas: `"Pet":{"type":"object","properties":{"owner":{"$ref":"#/components/schemas/Owner"}}},"Owner":{"type":"object"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := newTestGenerator(t, componentsSpec(test.schemas))
+			g.hoistInlineSchemas()
+			g.detectCycles()
+
+			var recursive []string
+			for _, cycle := range findCycles(schemaGraph(g.spec.Components.Schemas, schemaRefs)) {
+				recursive = append(recursive, cycle...)
+			}
+			sort.Strings(recursive)
+			if !reflect.DeepEqual(recursive, test.recursive) {
+				t.Errorf("recursive = %v, want %v", recursive, test.recursive)
+			}
+
+			if cycles := findCycles(schemaGraph(g.spec.Components.Schemas, eagerRefs)); len(cycles) > 0 {
+				t.Errorf("eager cycles left: %v", cycles)
+			}
+			for _, name := range test.dropped {
+				schema := g.spec.Components.Schemas[name]
+				if refs := eagerRefs(&schema); len(refs) > 0 {
+					t.Errorf("eager references of %s = %v, want none", name, refs)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateRecursiveTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		schemas string
+		// want are snippets expected in the generated types
+		want []string
+	}{
+		{
+			name: "self reference",
+			schemas: `"TreeNode":{"type":"object","required":["value"],"properties":{"value":{"type":"string"},
+				"parent":{"$ref":"#/components/schemas/TreeNode","nullable":true}}}`,
+			want: []string{"export interface TreeNode {", "  parent?: TreeNode | null;"},
+		},
+		{
+			name: "mutual recursion",
+			schemas: `"Person":{"type":"object","properties":{"employer":{"$ref":"#/components/schemas/Company"}}},
+				"Company":{"type":"object","properties":{"staff":{"type":"array","items":{"$ref":"#/components/schemas/Person"}}}}`,
+			want: []string{"export interface Person {", "  employer?: Company;", "export interface Company {", "  staff?: Person[];"},
+		},
+		{
+			name: "recursion through a hoisted inline schema",
+			schemas: `"Comment":{"type":"object","properties":{"replies":{"type":"array","items":{"type":"object",
+				"properties":{"comment":{"$ref":"#/components/schemas/Comment"}}}}}}`,
+			want: []string{"  replies?: CommentReplies[];", "export interface CommentReplies {", "  comment?: Comment;"},
+		},
+		{
+			name:    "recursion through arrays and maps",
+			schemas: `"Json":{"oneOf":[{"type":"string"},{"type":"array","items":{"$ref":"#/components/schemas/Json"}},{"type":"object","additionalProperties":{"$ref":"#/components/schemas/Json"}}]}`,
+			want:    []string{"export type Json = string | Json[] | { [key: string]: Json };"},
+		},
+		{
+			name:    "eager allOf self cycle",
+			schemas: `"Loop":{"allOf":[{"$ref":"#/components/schemas/Loop"},{"type":"object","properties":{"x":{"type":"string"}}}]}`,
+			want:    []string{"x?: string"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := newTestGenerator(t, componentsSpec(test.schemas))
+			if err := g.Generate(); err != nil {
+				t.Fatalf("Generate() failed: %v", err)
+			}
+
+			data, err := os.ReadFile(filepath.Join(g.outputDir, "types.ts"))
+			if err != nil {
+				t.Fatalf("failed to read types: %v", err)
+			}
+			types := string(data)
+
+			for _, line := range test.want {
+				if !strings.Contains(types, line) {
+					t.Errorf("types.ts is missing %q:\n%s", line, types)
+				}
+			}
+			if strings.Contains(types, "Loop &") {
+				t.Errorf("types.ts refers to Loop in its own declaration:\n%s", types)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package builder
+
+import (
+	"gogen/internal/models"
+	"gogen/internal/openapi"
+	"gogen/internal/utils"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// discriminatorVariant is a schema selected by a discriminator, with the values selecting it
+type discriminatorVariant struct {
+	schemaName string
+	values     []string
+}
+
+// refName returns the component schema name a reference or discriminator mapping target points to
+func refName(ref string) string {
+	return strings.TrimPrefix(ref, "#/components/schemas/")
+}
+
+// discriminatorVariants lists the variants of a discriminated schema, in declaration order. Variants without
+// an explicit mapping are selected by their schema name.
+func discriminatorVariants(schema *openapi.Schema) []discriminatorVariant {
+	var variants []discriminatorVariant
+	index := make(map[string]int)
+
+	add := func(schemaName, value string) {
+		i, exists := index[schemaName]
+		if !exists {
+			i = len(variants)
+			index[schemaName] = i
+			variants = append(variants, discriminatorVariant{schemaName: schemaName})
+		}
+		if value != "" {
+			variants[i].values = append(variants[i].values, value)
+		}
+	}
+
+	for _, subSchema := range append(append([]openapi.Schema{}, schema.OneOf...), schema.AnyOf...) {
+		if subSchema.Ref != "" {
+			add(refName(subSchema.Ref), "")
+		}
+	}
+
+	values := make([]string, 0, len(schema.Discriminator.Mapping))
+	for value := range schema.Discriminator.Mapping {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+
+	for _, value := range values {
+		add(refName(schema.Discriminator.Mapping[value]), value)
+	}
+
+	for i := range variants {
+		if len(variants[i].values) == 0 {
+			variants[i].values = []string{variants[i].schemaName}
+		}
+	}
+
+	return variants
+}
+
+// collectDiscriminatorValues maps variant schema names to the discriminator values selecting them, per property
+func (g *ClientGenerator) collectDiscriminatorValues() map[string]map[string][]string {
+	values := make(map[string]map[string][]string)
+
+	for _, schema := range g.spec.Components.Schemas {
+		if schema.Discriminator == nil || schema.Discriminator.PropertyName == "" {
+			continue
+		}
+
+		property := schema.Discriminator.PropertyName
+		for _, variant := range discriminatorVariants(&schema) {
+			if values[variant.schemaName] == nil {
+				values[variant.schemaName] = make(map[string][]string)
+			}
+			for _, value := range variant.values {
+				if !utils.Contains(values[variant.schemaName][property], value) {
+					values[variant.schemaName][property] = append(values[variant.schemaName][property], value)
+				}
+			}
+		}
+	}
+
+	for _, properties := range values {
+		for _, propertyValues := range properties {
+			sort.Strings(propertyValues)
+		}
+	}
+
+	return values
+}
+
+// buildDiscriminator builds the tagged union model of a discriminated oneOf/anyOf schema
+func (g *ClientGenerator) buildDiscriminator(name string, schema *openapi.Schema) *models.DiscriminatorModel {
+	if schema.Discriminator == nil || schema.Discriminator.PropertyName == "" {
+		return nil
+	}
+	if len(schema.OneOf) == 0 && len(schema.AnyOf) == 0 {
+		return nil
+	}
+
+	discriminator := &models.DiscriminatorModel{
+		PropertyName: schema.Discriminator.PropertyName,
+	}
+
+	unionName := g.adapter.FormatTypeName(name)
+	for _, variant := range discriminatorVariants(schema) {
+		typeName := g.adapter.FormatTypeName(variant.schemaName)
+
+		// guards are named after the union and the variant, as variants may be shared by several unions
+		guardName := "is" + unionName + typeName
+		for base, suffix := guardName, 2; g.guardNames[guardName]; suffix++ {
+			guardName = base + strconv.Itoa(suffix)
+		}
+		g.guardNames[guardName] = true
+
+		// the property may also be declared through allOf, in which case the variant type narrows it
+		properties, _, _ := g.objectProperties(&openapi.Schema{Ref: componentRef(variant.schemaName)}, map[string]bool{})
+		_, declared := properties[discriminator.PropertyName]
+
+		discriminator.Variants = append(discriminator.Variants, models.DiscriminatorVariantModel{
+			TypeName:  typeName,
+			GuardName: guardName,
+			Values:    variant.values,
+			ValueType: g.literalType(variant.values),
+			Tagged:    !declared,
+		})
+	}
+
+	return discriminator
+}
+
+// literalSchema describes a string restricted to the given discriminator values
+func literalSchema(values []string) *openapi.Schema {
+	enum := make([]any, len(values))
+	for i, value := range values {
+		enum[i] = value
+	}
+
+	return &openapi.Schema{Type: "string", Enum: enum}
+}
+
+// literalType converts discriminator values into a literal type of the target language
+func (g *ClientGenerator) literalType(values []string) string {
+	return g.adapter.ConvertType(literalSchema(values))
+}
@@ -0,0 +1,61 @@
+package builder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGuardNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		schemas string
+		// want maps the unions to the names of the guards of their variants
+		want map[string][]string
+	}{
+		{
+			name: "union",
+			schemas: `"Pet":{"oneOf":[{"$ref":"#/components/schemas/Cat"},{"$ref":"#/components/schemas/Dog"}],"discriminator":{"propertyName":"kind"}},
+				"Cat":{"type":"object","properties":{"kind":{"type":"string"}}},
+				"Dog":{"type":"object","properties":{"kind":{"type":"string"}}}`,
+			want: map[string][]string{"Pet": {"isPetCat", "isPetDog"}},
+		},
+		{
+			name: "unions sharing a variant",
+			schemas: `"Event":{"oneOf":[{"$ref":"#/components/schemas/Created"},{"$ref":"#/components/schemas/Deleted"}],"discriminator":{"propertyName":"type"}},
+				"Change":{"oneOf":[{"$ref":"#/components/schemas/Deleted"}],"discriminator":{"propertyName":"type"}},
+				"Created":{"type":"object","properties":{"type":{"type":"string"}}},
+				"Deleted":{"type":"object","properties":{"type":{"type":"string"}}}`,
+			want: map[string][]string{"Event": {"isEventCreated", "isEventDeleted"}, "Change": {"isChangeDeleted"}},
+		},
+		{
+			name: "input variants",
+			schemas: `"Event":{"oneOf":[{"$ref":"#/components/schemas/Created"},{"$ref":"#/components/schemas/Deleted"}],"discriminator":{"propertyName":"type"}},
+				"Created":{"type":"object","properties":{"type":{"type":"string"},"id":{"type":"string","readOnly":true}}},
+				"Deleted":{"type":"object","properties":{"type":{"type":"string"}}}`,
+			want: map[string][]string{
+				"Event":      {"isEventCreated", "isEventDeleted"},
+				"EventInput": {"isEventInputCreatedInput", "isEventInputDeleted"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := newTestGenerator(t, componentsSpec(test.schemas))
+			model := g.buildClientModel()
+
+			got := make(map[string][]string)
+			for _, typeModel := range model.Types {
+				if typeModel.Discriminator == nil {
+					continue
+				}
+				for _, variant := range typeModel.Discriminator.Variants {
+					got[typeModel.Name] = append(got[typeModel.Name], variant.GuardName)
+				}
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("guard names = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package builder
+
+import (
+	"encoding/json"
+	"gogen/internal/models"
+	"gogen/internal/openapi"
+)
+
+// buildDoc collects the documentation of a schema, rendering its default and example values as JSON
+func buildDoc(schema *openapi.Schema) models.DocModel {
+	if schema == nil {
+		return models.DocModel{}
+	}
+
+	doc := models.DocModel{
+		Title:       schema.Title,
+		Description: schema.Description,
+		Default:     docValue(schema.Default),
+		Example:     docValue(schema.Example),
+		Deprecated:  schema.Deprecated,
+	}
+	if doc.Example == "" && len(schema.Examples) > 0 {
+		doc.Example = docValue(schema.Examples[0])
+	}
+	if doc.Title == doc.Description {
+		doc.Title = ""
+	}
+
+	return doc
+}
+
+func docValue(value any) string {
+	if value == nil {
+		return ""
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
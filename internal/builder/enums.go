@@ -0,0 +1,83 @@
+package builder
+
+import (
+	"fmt"
+	"gogen/internal/models"
+	"gogen/internal/openapi"
+	"sort"
+)
+
+// enumStyles lists the supported ways of generating enum schemas
+var enumStyles = []string{"union", "const", "enum"}
+
+// buildEnumValues turns the values of an enum schema into named members. Names come from
+// x-enum-varnames when present and are derived from the values otherwise.
+func (g *ClientGenerator) buildEnumValues(schema *openapi.Schema) []models.EnumValueModel {
+	var values []models.EnumValueModel
+	used := make(map[string]bool)
+
+	for i, value := range schema.Enum {
+		// null is part of the type, not a member
+		if value == nil {
+			continue
+		}
+
+		name := fmt.Sprint(value)
+		if i < len(schema.EnumVarNames) && schema.EnumVarNames[i] != "" {
+			name = schema.EnumVarNames[i]
+		}
+		name = g.adapter.FormatEnumMemberName(name)
+
+		unique := name
+		for n := 2; used[unique]; n++ {
+			unique = fmt.Sprintf("%s%d", name, n)
+		}
+		used[unique] = true
+
+		enumValue := models.EnumValueModel{
+			Name:  unique,
+			Value: g.adapter.ConvertType(&openapi.Schema{Type: schema.Type, Enum: []any{value}}),
+		}
+		if i < len(schema.EnumDescriptions) {
+			enumValue.Description = schema.EnumDescriptions[i]
+		}
+		values = append(values, enumValue)
+	}
+
+	return values
+}
+
+// isEnumSchema checks if a schema is generated as an enum type
+func isEnumSchema(schema *openapi.Schema) bool {
+	return len(schema.Enum) > 0 && (schema.Type == "string" || schema.Type == "integer" || schema.Type == "number")
+}
+
+// markNullableEnums lets references to nullable enums add null when enums are generated as language enums,
+// whose members cannot include it
+func (g *ClientGenerator) markNullableEnums() {
+	if g.enumStyle != "enum" {
+		return
+	}
+
+	var names []string
+	for name, schema := range g.spec.Components.Schemas {
+		if isEnumSchema(&schema) && isNullableEnum(&schema) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	g.adapter.SetNullableRefs(names)
+}
+
+// isNullableEnum checks if an enum schema allows null, either by flag or by listing it as a value
+func isNullableEnum(schema *openapi.Schema) bool {
+	if schema.Nullable {
+		return true
+	}
+	for _, value := range schema.Enum {
+		if value == nil {
+			return true
+		}
+	}
+	return false
+}
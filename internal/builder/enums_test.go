@@ -0,0 +1,70 @@
+package builder
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateNullableEnums(t *testing.T) {
+	schemas := `"Status":{"type":"string","enum":["active","inactive"],"nullable":true},
+		"Level":{"type":"integer","enum":[1,2,null]},
+		"User":{"type":"object","properties":{"status":{"$ref":"#/components/schemas/Status"},"level":{"$ref":"#/components/schemas/Level"}}}`
+
+	tests := []struct {
+		style string
+		// want are snippets expected in the generated types
+		want []string
+	}{
+		{
+			style: "union",
+			want: []string{
+				"export type Status = 'active' | 'inactive' | null;",
+				"export type Level = 1 | 2 | null;",
+				"  status?: Status;",
+				"  level?: Level;",
+			},
+		},
+		{
+			style: "const",
+			want: []string{
+				"export type Status = (typeof Status)[keyof typeof Status] | null;",
+				"export type Level = (typeof Level)[keyof typeof Level] | null;",
+				"  status?: Status;",
+				"  level?: Level;",
+			},
+		},
+		{
+			style: "enum",
+			want: []string{
+				"export enum Status {",
+				"export enum Level {",
+				"  status?: Status | null;",
+				"  level?: Level | null;",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.style, func(t *testing.T) {
+			g := newTestGenerator(t, componentsSpec(schemas))
+			g.enumStyle = test.style
+			if err := g.Generate(); err != nil {
+				t.Fatalf("Generate() failed: %v", err)
+			}
+
+			data, err := os.ReadFile(filepath.Join(g.outputDir, "types.ts"))
+			if err != nil {
+				t.Fatalf("failed to read types: %v", err)
+			}
+			types := string(data)
+
+			for _, want := range test.want {
+				if !strings.Contains(types, want) {
+					t.Errorf("types.ts is missing %q:\n%s", want, types)
+				}
+			}
+		})
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"gogen/internal/models"
 	"gogen/internal/openapi"
 	"gogen/internal/templates"
-	"gogen/internal/utils"
 	"io"
 	"log"
 	"net/http"
@@ -26,11 +25,20 @@ type ClientGeneratorBuilder struct {
 	adapter      adapters.LanguageAdapter
 	templateMgr  *templates.Manager
 	templatesDir string
+	enumStyle    string
+	validation   bool
+	responseMode string
+	// excludeDeprecated leaves deprecated operations and webhooks out of the generated code
+	excludeDeprecated bool
+	// typeMappings maps languages to their format to type mappings
+	typeMappings map[string]map[string]string
 }
 
 func NewClientGeneratorBuilder() *ClientGeneratorBuilder {
 	return &ClientGeneratorBuilder{
-		templateMgr: templates.NewManager(),
+		templateMgr:  templates.NewManager(),
+		enumStyle:    "union",
+		responseMode: "data",
 	}
 }
 
@@ -63,6 +71,7 @@ func (b *ClientGeneratorBuilder) WithLanguage(language string) *ClientGeneratorB
 
 	switch strings.ToLower(language) {
 	case "typescript", "ts":
+		b.language = "typescript"
 		b.adapter = adapters.NewTypeScriptAdapter()
 	default:
 		log.Fatal("Unsupported language:", language)
@@ -76,22 +85,91 @@ func (b *ClientGeneratorBuilder) WithTemplatesDir(dir string) *ClientGeneratorBu
 	return b
 }
 
+// WithEnumStyle sets how enum schemas are generated: as literal unions, const objects or language enums
+func (b *ClientGeneratorBuilder) WithEnumStyle(style string) *ClientGeneratorBuilder {
+	style = strings.ToLower(style)
+	for _, supported := range enumStyles {
+		if style == supported {
+			b.enumStyle = style
+			return b
+		}
+	}
+
+	log.Fatal("Unsupported enum style:", style)
+	return b
+}
+
+// WithResponseMode sets what methods return: the response data, an envelope with the data, status and
+// headers, or both through additional WithResponse methods
+func (b *ClientGeneratorBuilder) WithResponseMode(mode string) *ClientGeneratorBuilder {
+	mode = strings.ToLower(mode)
+	for _, supported := range responseModes {
+		if mode == supported {
+			b.responseMode = mode
+			return b
+		}
+	}
+
+	log.Fatal("Unsupported response mode:", mode)
+	return b
+}
+
+// WithValidation enables generating validators from the constraints of the schemas
+func (b *ClientGeneratorBuilder) WithValidation(enabled bool) *ClientGeneratorBuilder {
+	b.validation = enabled
+	return b
+}
+
+// WithExcludeDeprecated leaves deprecated operations, and the callbacks they send, out of the generated code
+func (b *ClientGeneratorBuilder) WithExcludeDeprecated(exclude bool) *ClientGeneratorBuilder {
+	b.excludeDeprecated = exclude
+	return b
+}
+
+// WithTypeMappings loads format to type mappings from a JSON file with a section per language,
+// e.g. {"typescript": {"date-time": "Date", "int64": "bigint"}}
+func (b *ClientGeneratorBuilder) WithTypeMappings(path string) *ClientGeneratorBuilder {
+	if path == "" {
+		return b
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatal("Failed to read type mappings:", err)
+	}
+
+	if err := json.Unmarshal(data, &b.typeMappings); err != nil {
+		log.Fatal("Failed to parse type mappings:", err)
+	}
+
+	return b
+}
+
 func (b *ClientGeneratorBuilder) Build() *ClientGenerator {
 	if b.spec == nil || b.projectName == "" || b.outputDir == "" || b.adapter == nil {
 		log.Fatal("Missing required configuration")
 	}
 
+	if mappings, exists := b.typeMappings[b.language]; exists {
+		b.adapter.SetFormatMappings(mappings)
+	}
+
 	if err := b.templateMgr.LoadTemplates(b.templatesDir); err != nil {
 		log.Fatal("Failed to load templates:", err)
 	}
 
 	return &ClientGenerator{
-		spec:        b.spec,
-		projectName: b.projectName,
-		outputDir:   b.outputDir,
-		language:    b.language,
-		adapter:     b.adapter,
-		templateMgr: b.templateMgr,
+		spec:         b.spec,
+		projectName:  b.projectName,
+		outputDir:    b.outputDir,
+		language:     b.language,
+		adapter:      b.adapter,
+		templateMgr:  b.templateMgr,
+		enumStyle:    b.enumStyle,
+		validation:   b.validation,
+		responseMode: b.responseMode,
+
+		excludeDeprecated: b.excludeDeprecated,
 	}
 }
 
@@ -151,6 +229,26 @@ type ClientGenerator struct {
 	language    string
 	adapter     adapters.LanguageAdapter
 	templateMgr *templates.Manager
+	enumStyle   string
+	validation  bool
+	// responseMode is "data", "envelope" or "both", see WithResponseMode
+	responseMode      string
+	excludeDeprecated bool
+	// operationServers are the servers operations override the servers of the spec with
+	operationServers []models.ServerModel
+	// excluded lists the deprecated operations left out, and summary describes the generated code
+	excluded []string
+	summary  string
+	// deprecatedSchemas and deprecatedProperties count the deprecations of the component schemas
+	deprecatedSchemas    int
+	deprecatedProperties int
+
+	// discriminatorValues maps variant schema names to the discriminator values selecting them, per property
+	discriminatorValues map[string]map[string][]string
+	guardNames          map[string]bool
+	// inputNames maps component schema names to the names of their request variants
+	inputNames map[string]string
+	codecs     *codecBuilder
 }
 
 func (g *ClientGenerator) Generate() error {
@@ -167,10 +265,29 @@ func (g *ClientGenerator) Generate() error {
 		}
 	}
 
+	g.summary = g.buildSummary(model)
 	return nil
 }
 
+// Summary describes the generated code once Generate has run: what was generated, what is deprecated and
+// which deprecated operations were left out
+func (g *ClientGenerator) Summary() string {
+	return g.summary
+}
+
+// isExcluded checks if an operation is left out of the generated code
+func (g *ClientGenerator) isExcluded(operation *openapi.Operation) bool {
+	return g.excludeDeprecated && operation.Deprecated
+}
+
 func (g *ClientGenerator) buildClientModel() *models.ClientModel {
+	g.hoistInlineSchemas()
+	g.countDeprecations()
+	g.deriveReadWriteVariants()
+	g.detectCycles()
+	g.markNullableEnums()
+	g.buildCodecs()
+
 	model := &models.ClientModel{
 		ProjectName:  g.projectName,
 		Description:  g.spec.Info.Description,
@@ -178,31 +295,45 @@ func (g *ClientGenerator) buildClientModel() *models.ClientModel {
 		Dependencies: g.adapter.GetDependencies(),
 		Methods:      g.buildMethods(),
 		Types:        g.buildTypes(),
+
+		SecuritySchemes: g.buildSecuritySchemes(),
+		EnumStyle:       g.enumStyle,
+		ResponseMode:    g.responseMode,
 	}
 
 	if len(g.spec.Servers) > 0 {
-		model.BaseURL = g.spec.Servers[0].URL
+		model.BaseURL = resolveServerURL(g.spec.Servers[0])
+		model.Servers = g.buildServers()
+	}
+	model.OperationServers = g.operationServers
+
+	g.buildShapes(model)
+
+	if g.validation {
+		model.Validation = true
+		model.Validators = g.buildValidators()
 	}
 
+	model.Webhooks = g.buildWebhooks()
+
 	return model
 }
 
 func (g *ClientGenerator) buildMethods() []models.MethodModel {
 	var methods []models.MethodModel
 
-	for path, pathItem := range g.spec.Paths {
-		operations := map[string]*openapi.Operation{
-			"GET":    pathItem.Get,
-			"POST":   pathItem.Post,
-			"PUT":    pathItem.Put,
-			"DELETE": pathItem.Delete,
-			"PATCH":  pathItem.Patch,
-		}
+	for _, path := range sortedPaths(g.spec.Paths) {
+		operations := pathOperations(g.spec.Paths[path])
 
-		for httpMethod, operation := range operations {
+		for _, httpMethod := range httpMethods {
+			operation := operations[httpMethod]
 			if operation == nil {
 				continue
 			}
+			if g.isExcluded(operation) {
+				g.excluded = append(g.excluded, httpMethod+" "+path)
+				continue
+			}
 
 			method := g.buildMethodModel(path, httpMethod, operation)
 			methods = append(methods, method)
@@ -217,6 +348,7 @@ func (g *ClientGenerator) buildMethodModel(path, httpMethod string, operation *o
 	var requestBody *models.RequestBodyModel
 
 	seen := make(map[string]bool)
+	names := make(map[string]bool)
 
 	for _, param := range operation.Parameters {
 
@@ -227,13 +359,14 @@ func (g *ClientGenerator) buildMethodModel(path, httpMethod string, operation *o
 		}
 		seen[paramKey] = true
 
-		parameters = append(parameters, models.ParameterModel{
-			Name:        g.adapter.FormatPropertyName(param.Name),
-			Type:        g.adapter.ConvertType(param.Schema),
-			In:          param.In,
-			Required:    param.Required,
-			Description: param.Description,
-		})
+		// the same wire name may be used in different locations, e.g. an "id" path and query parameter
+		name := g.adapter.FormatParameterName(param.Name)
+		if names[name] {
+			name = g.adapter.FormatParameterName(param.Name + " " + param.In)
+		}
+		names[name] = true
+
+		parameters = append(parameters, g.buildParameterModel(name, param))
 	}
 
 	// sort parameters by required so that they can be rendered in the correct order
@@ -245,120 +378,311 @@ func (g *ClientGenerator) buildMethodModel(path, httpMethod string, operation *o
 		return parameters[i].Name < parameters[j].Name
 	})
 
-	if operation.RequestBody != nil {
-		requestBody = &models.RequestBodyModel{
-			Type:     g.getRequestBodyType(operation.RequestBody),
-			Required: operation.RequestBody.Required,
+	if operation.RequestBody != nil && len(operation.RequestBody.Content) > 0 {
+		requestBody = g.buildRequestBody(operation.RequestBody, names)
+	}
+
+	methodName := g.adapter.FormatMethodName(operation.OperationID, httpMethod, operation.Tags)
+	method := models.MethodModel{
+		Name:        methodName,
+		HTTPMethod:  httpMethod,
+		Summary:     operation.Summary,
+		Description: operation.Description,
+		Doc:         models.DocModel{Title: operation.Summary, Description: operation.Description, Deprecated: operation.Deprecated},
+		Parameters:  parameters,
+		RequestBody: requestBody,
+		Security:    g.buildSecurity(operation),
+		Server:      g.buildOperationServer(path, methodName, operation),
+	}
+	g.buildResponses(&method, operationTypeName(path, httpMethod, operation), operation)
+
+	// partition parameters by location, pointing path templates at the safe identifiers
+	for _, param := range parameters {
+		switch param.In {
+		case "path":
+			method.PathParams = append(method.PathParams, param)
+			path = strings.ReplaceAll(path, "{"+param.WireName+"}", "{"+param.Name+"}")
+		case "query":
+			method.QueryParams = append(method.QueryParams, param)
+		case "header":
+			method.HeaderParams = append(method.HeaderParams, param)
+		case "cookie":
+			method.CookieParams = append(method.CookieParams, param)
 		}
 	}
+	method.Path = g.adapter.FormatPath(path, httpMethod)
 
-	return models.MethodModel{
-		Name:         g.adapter.FormatMethodName(operation.OperationID, httpMethod, operation.Tags),
-		HTTPMethod:   httpMethod,
-		Path:         g.adapter.FormatPath(path, httpMethod),
-		Summary:      operation.Summary,
-		Description:  operation.Description,
-		Parameters:   parameters,
-		RequestBody:  requestBody,
-		ResponseType: g.getResponseType(operation),
-	}
+	return method
 }
 
-func (g *ClientGenerator) buildTypes() []models.TypeModel {
-	var types []models.TypeModel
+func (g *ClientGenerator) buildParameterModel(name string, param openapi.Parameter) models.ParameterModel {
+	// parameters are sent by the client, so they use the request variants of component schemas
+	schema := g.inputSchema(param.Schema)
+	parameter := models.ParameterModel{
+		Name:          name,
+		WireName:      param.Name,
+		Type:          g.adapter.ConvertType(schema),
+		In:            param.In,
+		Required:      param.Required || param.In == "path",
+		Description:   param.Description,
+		Style:         param.Style,
+		AllowReserved: param.AllowReserved,
+		Shape:         g.codecs.render(schema),
+	}
+
+	// parameters may use a media type instead of a schema, in which case the value is serialized as a whole
+	if param.Schema == nil && len(param.Content) > 0 {
+		contentTypes := make([]string, 0, len(param.Content))
+		for contentType := range param.Content {
+			contentTypes = append(contentTypes, contentType)
+		}
+		sort.Strings(contentTypes)
 
-	for name, schema := range g.spec.Components.Schemas {
-		var typeModel *models.TypeModel
+		contentSchema := g.inputSchema(param.Content[contentTypes[0]].Schema)
+		parameter.ContentType = contentTypes[0]
+		parameter.Type = g.adapter.ConvertType(contentSchema)
+		parameter.Shape = g.codecs.render(contentSchema)
+	}
 
-		switch schema.Type {
-		case "object":
-			if len(schema.Properties) > 0 {
-				typeModel = g.processObjectSchema(name, &schema)
-			}
-		case "array", "string", "integer", "number", "boolean":
-			typeModel = &models.TypeModel{
-				Name: g.adapter.FormatTypeName(name),
-				Type: g.adapter.ConvertType(&schema),
-			}
+	// default serialization styles as defined by the OpenAPI specification
+	if parameter.Style == "" {
+		switch param.In {
+		case "query", "cookie":
+			parameter.Style = "form"
 		default:
-			typeModel = &models.TypeModel{
-				Name: g.adapter.FormatTypeName(name),
-				Type: g.adapter.ConvertType(&schema),
-			}
+			parameter.Style = "simple"
+		}
+	}
+
+	parameter.Explode = parameter.Style == "form"
+	if param.Explode != nil {
+		parameter.Explode = *param.Explode
+	}
+
+	return parameter
+}
+
+// supportedSecuritySchemes lists the security scheme types the generated clients can apply themselves
+var supportedSecuritySchemes = map[string]bool{
+	"apiKey":        true,
+	"http":          true,
+	"oauth2":        true,
+	"openIdConnect": true,
+}
+
+func (g *ClientGenerator) buildSecuritySchemes() []models.SecuritySchemeModel {
+	var schemes []models.SecuritySchemeModel
+
+	for name, scheme := range g.spec.Components.SecuritySchemes {
+		if !supportedSecuritySchemes[scheme.Type] {
+			continue
+		}
+
+		model := models.SecuritySchemeModel{
+			Name:             name,
+			Identifier:       g.adapter.FormatParameterName(name),
+			Type:             scheme.Type,
+			Description:      scheme.Description,
+			Scheme:           strings.ToLower(scheme.Scheme),
+			In:               scheme.In,
+			ParamName:        scheme.Name,
+			BearerFormat:     scheme.BearerFormat,
+			OpenIDConnectURL: scheme.OpenIDConnectURL,
 		}
 
-		if typeModel != nil {
-			types = append(types, *typeModel)
+		if flow := tokenFlow(scheme.Flows); flow != nil {
+			model.TokenURL = flow.TokenURL
+			model.RefreshURL = flow.RefreshURL
 		}
+
+		schemes = append(schemes, model)
 	}
 
-	return types
+	sort.Slice(schemes, func(i, j int) bool {
+		return schemes[i].Name < schemes[j].Name
+	})
+
+	return schemes
 }
 
-func (g *ClientGenerator) processObjectSchema(name string, schema *openapi.Schema) *models.TypeModel {
-	if len(schema.Properties) == 0 {
-		// TODO: handle additional properties
+// tokenFlow picks the OAuth2 flow whose token endpoint the generated clients fetch and refresh tokens from,
+// preferring client credentials since it needs no user interaction
+func tokenFlow(flows *openapi.OAuthFlows) *openapi.OAuthFlow {
+	if flows == nil {
+		return nil
+	}
 
-		return &models.TypeModel{
-			Name: g.adapter.FormatTypeName(name),
-			Type: g.adapter.ConvertType(schema),
+	for _, flow := range []*openapi.OAuthFlow{flows.ClientCredentials, flows.AuthorizationCode, flows.Password} {
+		if flow != nil && flow.TokenURL != "" {
+			return flow
 		}
 	}
 
-	var properties []models.PropertyModel
-	for propName, propSchema := range schema.Properties {
+	return nil
+}
 
-		isRequired := false
-		if schema.Required != nil {
-			if schema.Required.BoolValue != nil && *schema.Required.BoolValue {
-				isRequired = true
-			}
+// buildSecurity resolves the security requirements of an operation, falling back to the global ones.
+// An empty result means the operation requires no authentication.
+func (g *ClientGenerator) buildSecurity(operation *openapi.Operation) []models.SecurityRequirementModel {
+	requirements := g.spec.Security
+	if operation.Security != nil {
+		requirements = *operation.Security
+	}
 
-			if schema.Required.ArrayValue != nil {
-				isRequired = utils.Contains(schema.Required.ArrayValue, propName)
+	var security []models.SecurityRequirementModel
+	for _, requirement := range requirements {
+		names := make([]string, 0, len(requirement))
+		for name := range requirement {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		model := models.SecurityRequirementModel{}
+		supported := true
+		for _, name := range names {
+			scheme, exists := g.spec.Components.SecuritySchemes[name]
+			if !exists || !supportedSecuritySchemes[scheme.Type] {
+				supported = false
+				break
 			}
+			model.Schemes = append(model.Schemes, g.adapter.FormatParameterName(name))
 		}
 
-		properties = append(properties, models.PropertyModel{
-			Name:     g.adapter.FormatPropertyName(propName),
-			Type:     g.adapter.ConvertType(propSchema),
-			Required: isRequired,
-		})
+		if supported {
+			security = append(security, model)
+		}
 	}
 
-	return &models.TypeModel{
-		Name:       g.adapter.FormatTypeName(name),
-		Type:       g.adapter.ConvertType(schema),
-		Properties: properties,
+	return security
+}
+
+func (g *ClientGenerator) buildTypes() []models.TypeModel {
+	var types []models.TypeModel
+
+	g.discriminatorValues = g.collectDiscriminatorValues()
+	g.guardNames = make(map[string]bool)
+
+	names := make([]string, 0, len(g.spec.Components.Schemas))
+	for name := range g.spec.Components.Schemas {
+		names = append(names, name)
 	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		schema := g.spec.Components.Schemas[name]
+		types = append(types, *g.buildTypeModel(name, &schema))
+	}
+
+	return types
 }
 
-func (g *ClientGenerator) getRequestBodyType(requestBody *openapi.RequestBody) string {
-	for _, mediaType := range requestBody.Content {
-		if mediaType.Schema != nil {
-			return g.adapter.ConvertType(mediaType.Schema)
-		}
+// buildTypeModel builds the type of a component schema, choosing its kind from the shape of the schema
+func (g *ClientGenerator) buildTypeModel(name string, schema *openapi.Schema) *models.TypeModel {
+	typeModel := &models.TypeModel{
+		Name: g.adapter.FormatTypeName(name),
+		Kind: "alias",
+		Type: g.adapter.ConvertType(schema),
+		Doc:  buildDoc(schema),
 	}
-	return g.adapter.ConvertType(nil)
+
+	switch {
+	case len(schema.AllOf) > 0:
+		if objectModel := g.processAllOfSchema(name, schema); objectModel != nil {
+			return objectModel
+		}
+		typeModel.Kind = "intersection"
+	case len(schema.OneOf) > 0 || len(schema.AnyOf) > 0:
+		typeModel.Kind = "union"
+		typeModel.Discriminator = g.buildDiscriminator(name, schema)
+	case isEnumSchema(schema):
+		typeModel.Kind = "enum"
+		typeModel.EnumValues = g.buildEnumValues(schema)
+		typeModel.Nullable = isNullableEnum(schema)
+	case schema.Nullable:
+		// null cannot be part of an object declaration, so nullable objects stay aliases
+	case schema.Type == "object" || (schema.Type == "" && (schema.Properties != nil || schema.AdditionalProperties != nil)):
+		return g.processObjectSchema(name, schema)
+	}
+
+	return typeModel
 }
 
-func (g *ClientGenerator) getResponseType(operation *openapi.Operation) string {
-	for code, response := range operation.Responses {
-		if strings.HasPrefix(code, "2") {
-			for _, mediaType := range response.Content {
-				if mediaType.Schema != nil {
-					return g.adapter.ConvertType(mediaType.Schema)
+func (g *ClientGenerator) processObjectSchema(name string, schema *openapi.Schema) *models.TypeModel {
+	// objects without fixed properties are map types
+	if len(schema.Properties) == 0 {
+		return &models.TypeModel{
+			Name: g.adapter.FormatTypeName(name),
+			Kind: "map",
+			Type: g.adapter.ConvertType(schema),
+			Doc:  buildDoc(schema),
+		}
+	}
+
+	// variants of discriminated unions narrow the discriminator property to the values selecting them
+	if literals := g.discriminatorValues[name]; len(literals) > 0 {
+		narrowed := cloneSchema(schema)
+		required := &openapi.FlexibleRequired{}
+		for propName := range narrowed.Properties {
+			if values, exists := literals[propName]; exists {
+				literal := literalSchema(values)
+				if original := narrowed.Properties[propName]; original != nil {
+					literal.Title, literal.Description, literal.Deprecated = original.Title, original.Description, original.Deprecated
 				}
+				narrowed.Properties[propName] = literal
+			}
+			if literals[propName] != nil || schema.IsRequired(propName) {
+				required.ArrayValue = append(required.ArrayValue, propName)
 			}
 		}
+		sort.Strings(required.ArrayValue)
+		narrowed.Required = required
+		schema = narrowed
+	}
+
+	propNames := make([]string, 0, len(schema.Properties))
+	for propName := range schema.Properties {
+		propNames = append(propNames, propName)
+	}
+	sort.Strings(propNames)
+
+	var properties []models.PropertyModel
+	for _, propName := range propNames {
+		propSchema := schema.Properties[propName]
+		isRequired := schema.IsRequired(propName)
+
+		property := models.PropertyModel{
+			Name:     g.adapter.FormatPropertyName(propName),
+			Type:     g.adapter.ConvertType(propSchema),
+			Required: isRequired,
+		}
+		if propSchema != nil {
+			property.ReadOnly = propSchema.ReadOnly
+			property.Doc = buildDoc(propSchema)
+		}
+		properties = append(properties, property)
+	}
+
+	return &models.TypeModel{
+		Name:               g.adapter.FormatTypeName(name),
+		Kind:               "object",
+		Type:               g.adapter.ConvertType(schema),
+		Properties:         properties,
+		IndexSignatureType: g.adapter.ConvertIndexSignatureType(schema),
+		Doc:                buildDoc(schema),
 	}
-	return g.adapter.ConvertType(nil)
 }
 
 func (g *ClientGenerator) getRequiredFiles() []string {
 	switch g.language {
 	case "typescript", "ts":
-		return []string{"package.json", "tsconfig.json", "client", "types", "index", "README.md"}
+		files := []string{"package.json", "tsconfig.json", "runtime", "client", "types", "index", "README.md"}
+		if g.validation {
+			files = append(files, "validation")
+		}
+		if len(g.webhookOperations()) > 0 {
+			files = append(files, "webhooks")
+		}
+		return files
 	case "python", "py":
 		return []string{"setup.py", "requirements.txt", "client", "types", "__init__", "README.md"}
 	}
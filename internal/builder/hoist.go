@@ -0,0 +1,243 @@
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+	"gogen/internal/openapi"
+	"gogen/internal/utils"
+	"sort"
+	"strings"
+)
+
+// httpMethods lists the operations of a path item in the order they are processed
+var httpMethods = []string{"GET", "POST", "PUT", "DELETE", "PATCH"}
+
+// pathOperations returns the operations of a path item keyed by HTTP method
+func pathOperations(pathItem openapi.PathItem) map[string]*openapi.Operation {
+	return map[string]*openapi.Operation{
+		"GET":    pathItem.Get,
+		"POST":   pathItem.Post,
+		"PUT":    pathItem.Put,
+		"DELETE": pathItem.Delete,
+		"PATCH":  pathItem.Patch,
+	}
+}
+
+// sortedPaths returns the paths of the spec in a deterministic order
+func sortedPaths(paths map[string]openapi.PathItem) []string {
+	names := make([]string, 0, len(paths))
+	for path := range paths {
+		names = append(names, path)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// operationTypeName returns the prefix for the names of types hoisted out of an operation
+func operationTypeName(path, httpMethod string, operation *openapi.Operation) string {
+	if operation.OperationID != "" {
+		return utils.ToPascalIdentifier(operation.OperationID)
+	}
+	return utils.ToPascalIdentifier(strings.ToLower(httpMethod) + " " + path)
+}
+
+// hoister moves inline object and enum schemas into named component schemas
+type hoister struct {
+	schemas map[string]openapi.Schema
+	// names maps the fingerprints of named schemas to their names, so identical schemas share a type
+	names map[string]string
+}
+
+// hoistInlineSchemas replaces inline objects and enums in components, parameters, request bodies and
+// responses, including those of webhooks and callbacks, with references to new component schemas. Names
+// are derived from where the schema is used, e.g. CreateUserRequest or GetOrderResponseItems, unless the
+// schema sets x-gogen-type-name.
+func (g *ClientGenerator) hoistInlineSchemas() {
+	h := &hoister{
+		schemas: make(map[string]openapi.Schema, len(g.spec.Components.Schemas)),
+		names:   make(map[string]string),
+	}
+
+	names := make([]string, 0, len(g.spec.Components.Schemas))
+	for name, schema := range g.spec.Components.Schemas {
+		names = append(names, name)
+		h.schemas[name] = schema
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		schema := h.schemas[name]
+		if fingerprint := schemaFingerprint(&schema); h.names[fingerprint] == "" {
+			h.names[fingerprint] = name
+		}
+	}
+
+	for _, name := range names {
+		component := h.schemas[name]
+		schema := cloneSchema(&component)
+		h.hoistChildren(schema, name)
+		h.schemas[name] = *schema
+	}
+
+	for _, path := range sortedPaths(g.spec.Paths) {
+		operations := pathOperations(g.spec.Paths[path])
+		for _, httpMethod := range httpMethods {
+			operation := operations[httpMethod]
+			if operation == nil || g.isExcluded(operation) {
+				continue
+			}
+
+			h.hoistOperation(operationTypeName(path, httpMethod, operation), operation)
+		}
+	}
+
+	for _, webhook := range g.webhookOperations() {
+		h.hoistOperation(webhook.typeName, webhook.operation)
+	}
+
+	g.spec.Components.Schemas = h.schemas
+}
+
+func (h *hoister) hoistOperation(name string, operation *openapi.Operation) {
+	for i := range operation.Parameters {
+		param := &operation.Parameters[i]
+		paramName := name + utils.ToPascalIdentifier(param.Name)
+
+		param.Schema = h.hoist(param.Schema, paramName)
+		h.hoistContent(param.Content, paramName)
+	}
+
+	if operation.RequestBody != nil {
+		h.hoistContent(operation.RequestBody.Content, name+"Request")
+	}
+
+	codes := make([]string, 0, len(operation.Responses))
+	for code := range operation.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		responseName := name + "Response"
+		if code == "default" {
+			responseName = name + "DefaultResponse"
+		} else if !strings.HasPrefix(code, "2") {
+			responseName = name + strings.ToUpper(code) + "Response"
+		}
+		h.hoistContent(operation.Responses[code].Content, responseName)
+	}
+}
+
+func (h *hoister) hoistContent(content map[string]openapi.MediaType, name string) {
+	contentTypes := make([]string, 0, len(content))
+	for contentType := range content {
+		contentTypes = append(contentTypes, contentType)
+	}
+	sort.Strings(contentTypes)
+
+	for _, contentType := range contentTypes {
+		mediaType := content[contentType]
+		mediaType.Schema = h.hoist(mediaType.Schema, name)
+		content[contentType] = mediaType
+	}
+}
+
+// hoistable checks if an inline schema deserves a named type
+func hoistable(schema *openapi.Schema) bool {
+	if schema.Ref != "" {
+		return false
+	}
+	if len(schema.Enum) > 0 {
+		return true
+	}
+	return len(schema.Properties) > 0 && (schema.Type == "object" || schema.Type == "")
+}
+
+// hoist returns a reference to a named schema for hoistable schemas, after hoisting their children.
+// Flags describing the position rather than the type, like nullable and readOnly, stay on the reference.
+func (h *hoister) hoist(schema *openapi.Schema, name string) *openapi.Schema {
+	if schema == nil {
+		return nil
+	}
+
+	schema = cloneSchema(schema)
+	if !hoistable(schema) {
+		h.hoistChildren(schema, name)
+		return schema
+	}
+
+	ref := &openapi.Schema{
+		Nullable:  schema.Nullable,
+		ReadOnly:  schema.ReadOnly,
+		WriteOnly: schema.WriteOnly,
+	}
+	schema.Nullable, schema.ReadOnly, schema.WriteOnly = false, false, false
+
+	if schema.TypeName != "" {
+		name = schema.TypeName
+	}
+
+	fingerprint := schemaFingerprint(schema)
+	if existing, exists := h.names[fingerprint]; exists {
+		ref.Ref = componentRef(existing)
+		return ref
+	}
+
+	name = h.uniqueName(name)
+	h.names[fingerprint] = name
+	h.schemas[name] = *schema
+
+	h.hoistChildren(schema, name)
+	h.schemas[name] = *schema
+
+	ref.Ref = componentRef(name)
+	return ref
+}
+
+// hoistChildren hoists the inline subschemas of a schema, naming them after the schema
+func (h *hoister) hoistChildren(schema *openapi.Schema, name string) {
+	propNames := make([]string, 0, len(schema.Properties))
+	for propName := range schema.Properties {
+		propNames = append(propNames, propName)
+	}
+	sort.Strings(propNames)
+
+	for _, propName := range propNames {
+		schema.Properties[propName] = h.hoist(schema.Properties[propName], name+utils.ToPascalIdentifier(propName))
+	}
+
+	// arrays are never hoisted themselves, so their items take the name of the array
+	schema.Items = h.hoist(schema.Items, name)
+
+	if schema.AdditionalProperties != nil {
+		schema.AdditionalProperties.SchemaValue = h.hoist(schema.AdditionalProperties.SchemaValue, name+"Value")
+	}
+
+	for _, variants := range [][]openapi.Schema{schema.OneOf, schema.AnyOf} {
+		for i := range variants {
+			variants[i] = *h.hoist(&variants[i], fmt.Sprintf("%sOption%d", name, i+1))
+		}
+	}
+
+	// allOf members are parts of the schema itself rather than types of their own
+	for i := range schema.AllOf {
+		h.hoistChildren(&schema.AllOf[i], name)
+	}
+}
+
+func (h *hoister) uniqueName(name string) string {
+	unique := name
+	for i := 2; hasSchema(h.schemas, unique); i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	return unique
+}
+
+// schemaFingerprint identifies the structure of a schema, so structurally identical schemas can be shared
+func schemaFingerprint(schema *openapi.Schema) string {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,66 @@
+package builder
+
+import "testing"
+
+func TestParameterNameCollisions(t *testing.T) {
+	tests := []struct {
+		name       string
+		parameters string
+		// want maps the parameter names of the method to their wire names and locations
+		want map[string]string
+	}{
+		{
+			name:       "distinct names",
+			parameters: `{"name":"id","in":"path","required":true,"schema":{"type":"string"}},{"name":"limit","in":"query","schema":{"type":"integer"}}`,
+			want:       map[string]string{"id": "id:path", "limit": "limit:query"},
+		},
+		{
+			name:       "same name in path and query",
+			parameters: `{"name":"id","in":"path","required":true,"schema":{"type":"string"}},{"name":"id","in":"query","schema":{"type":"string"}}`,
+			want:       map[string]string{"id": "id:path", "idQuery": "id:query"},
+		},
+		{
+			name:       "same snake case name in query and header",
+			parameters: `{"name":"request_id","in":"query","schema":{"type":"string"}},{"name":"request_id","in":"header","schema":{"type":"string"}}`,
+			want:       map[string]string{"request_id": "request_id:query", "requestIdHeader": "request_id:header"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec := `{"openapi":"3.0.0","info":{"title":"Test","version":"1"},"paths":{"/items/{id}":{"get":{
+				"operationId":"getItem","parameters":[` + test.parameters + `],"responses":{"204":{"description":"ok"}}}}}}`
+			g := newTestGenerator(t, spec)
+			model := g.buildClientModel()
+
+			got := make(map[string]string)
+			for _, parameter := range model.Methods[0].Parameters {
+				got[parameter.Name] = parameter.WireName + ":" + parameter.In
+			}
+			if len(got) != len(test.want) {
+				t.Fatalf("parameters = %v, want %v", got, test.want)
+			}
+			for name, wire := range test.want {
+				if got[name] != wire {
+					t.Errorf("parameter %s = %q, want %q", name, got[name], wire)
+				}
+			}
+		})
+	}
+}
+
+func TestParameterInputVariants(t *testing.T) {
+	spec := `{"openapi":"3.0.0","info":{"title":"Test","version":"1"},"paths":{"/search":{"get":{"operationId":"search",
+		"parameters":[{"name":"filter","in":"query","style":"deepObject","schema":{"$ref":"#/components/schemas/Filter"}},
+			{"name":"meta","in":"query","content":{"application/json":{"schema":{"$ref":"#/components/schemas/Filter"}}}}],
+		"responses":{"204":{"description":"ok"}}}}},
+		"components":{"schemas":{"Filter":{"type":"object","properties":{"q":{"type":"string"},"total":{"type":"integer","readOnly":true}}}}}}`
+	g := newTestGenerator(t, spec)
+	model := g.buildClientModel()
+
+	for _, parameter := range model.Methods[0].Parameters {
+		if parameter.Type != "FilterInput" {
+			t.Errorf("type of parameter %s = %q, want %q", parameter.Name, parameter.Type, "FilterInput")
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package builder
+
+import (
+	"fmt"
+	"gogen/internal/openapi"
+	"sort"
+)
+
+func isReadOnly(schema *openapi.Schema) bool {
+	return schema != nil && schema.ReadOnly
+}
+
+func isWriteOnly(schema *openapi.Schema) bool {
+	return schema != nil && schema.WriteOnly
+}
+
+// hasFlaggedProperty checks if a schema or one of its inline subschemas has a property matching the flag
+func hasFlaggedProperty(schema *openapi.Schema, flagged func(*openapi.Schema) bool) bool {
+	found := false
+	walkSchema(schema, func(s *openapi.Schema) {
+		for _, property := range s.Properties {
+			if flagged(property) {
+				found = true
+			}
+		}
+	})
+	return found
+}
+
+// deriveReadWriteVariants splits component schemas with readOnly or writeOnly properties into the shape
+// sent by clients and the shape returned by servers. Response shapes keep the schema name and lose their
+// writeOnly properties; request shapes are added as "<Name>Input" schemas without readOnly properties.
+// Schemas referencing a schema with an input variant get an input variant too.
+func (g *ClientGenerator) deriveReadWriteVariants() {
+	schemas := g.spec.Components.Schemas
+
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	needsInput := make(map[string]bool)
+	for _, name := range names {
+		schema := schemas[name]
+		// writeOnly properties are dropped from the response shape, so requests need their own variant too
+		if hasFlaggedProperty(&schema, isReadOnly) || hasFlaggedProperty(&schema, isWriteOnly) {
+			needsInput[name] = true
+		}
+	}
+
+	// propagate through references until no more schemas are affected, which also terminates on cycles
+	for changed := true; changed; {
+		changed = false
+		for _, name := range names {
+			if needsInput[name] {
+				continue
+			}
+
+			schema := schemas[name]
+			for _, ref := range schemaRefs(&schema) {
+				if needsInput[ref] {
+					needsInput[name] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	g.inputNames = make(map[string]string)
+	for _, name := range names {
+		if !needsInput[name] {
+			continue
+		}
+
+		inputName := name + "Input"
+		for i := 2; hasSchema(schemas, inputName); i++ {
+			inputName = fmt.Sprintf("%sInput%d", name, i)
+		}
+		g.inputNames[name] = inputName
+	}
+
+	derived := make(map[string]openapi.Schema, len(schemas)+len(g.inputNames))
+	for _, name := range names {
+		schema := schemas[name]
+		derived[name] = *g.outputSchema(&schema)
+
+		if inputName, exists := g.inputNames[name]; exists {
+			derived[inputName] = *g.inputSchema(&schema)
+		}
+	}
+
+	g.spec.Components.Schemas = derived
+}
+
+func hasSchema(schemas map[string]openapi.Schema, name string) bool {
+	_, exists := schemas[name]
+	return exists
+}
+
+// inputSchema returns a copy of the schema for request positions, without readOnly properties and
+// referencing the input variants of component schemas
+func (g *ClientGenerator) inputSchema(schema *openapi.Schema) *openapi.Schema {
+	if schema == nil {
+		return nil
+	}
+
+	input := cloneSchema(schema)
+	walkSchema(input, func(s *openapi.Schema) {
+		dropProperties(s, isReadOnly)
+
+		if s.Discriminator != nil && len(g.inputNames) > 0 {
+			// implicit mappings select variants by schema name, which must survive the renaming
+			for _, variant := range discriminatorVariants(s) {
+				for _, value := range variant.values {
+					s.Discriminator.Mapping[value] = componentRef(variant.schemaName)
+				}
+			}
+			for value, ref := range s.Discriminator.Mapping {
+				if inputName, exists := g.inputNames[refName(ref)]; exists {
+					s.Discriminator.Mapping[value] = componentRef(inputName)
+				}
+			}
+		}
+
+		if inputName, exists := g.inputNames[refName(s.Ref)]; exists && s.Ref != "" {
+			s.Ref = componentRef(inputName)
+		}
+	})
+
+	return input
+}
+
+// outputSchema returns a copy of the schema for response positions, without writeOnly properties
+func (g *ClientGenerator) outputSchema(schema *openapi.Schema) *openapi.Schema {
+	if schema == nil {
+		return nil
+	}
+
+	output := cloneSchema(schema)
+	walkSchema(output, func(s *openapi.Schema) {
+		dropProperties(s, isWriteOnly)
+	})
+
+	return output
+}
+
+// dropProperties removes the properties matching the flag from a schema and its required list
+func dropProperties(schema *openapi.Schema, flagged func(*openapi.Schema) bool) {
+	for name, property := range schema.Properties {
+		if !flagged(property) {
+			continue
+		}
+
+		delete(schema.Properties, name)
+
+		if schema.Required != nil && schema.Required.ArrayValue != nil {
+			var required []string
+			for _, requiredName := range schema.Required.ArrayValue {
+				if requiredName != name {
+					required = append(required, requiredName)
+				}
+			}
+			schema.Required.ArrayValue = required
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package builder
+
+import (
+	"encoding/json"
+	"gogen/internal/models"
+	"gogen/internal/openapi"
+	"gogen/internal/utils"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// responseModes lists the supported return values of methods, see WithResponseMode
+var responseModes = []string{"data", "envelope", "both"}
+
+// headerSpec is the language-neutral form of how a response header is parsed from its text, rendered as JSON
+type headerSpec struct {
+	Type  string      `json:"type"`
+	Items *headerSpec `json:"items,omitempty"`
+	// Codec converts the text of headers whose format is mapped to another type, like date-time to Date
+	Codec string `json:"codec,omitempty"`
+}
+
+// isSuccessStatus checks if a response status code, or range like 2XX, is a successful one
+func isSuccessStatus(code string) bool {
+	return strings.HasPrefix(code, "2")
+}
+
+// renderShapes renders a map of codec shapes by status code, or an empty string when there are none
+func renderShapes(shapes map[string]string) string {
+	if len(shapes) == 0 {
+		return ""
+	}
+
+	raw := make(map[string]json.RawMessage, len(shapes))
+	for code, shape := range shapes {
+		raw[code] = json.RawMessage(shape)
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// buildResponses models the responses of an operation by status code. The method returns any of the
+// successful response types and throws the error responses, typed by status. Name is the type name of
+// the operation, which names the error type.
+func (g *ClientGenerator) buildResponses(method *models.MethodModel, name string, operation *openapi.Operation) {
+	codes := make([]string, 0, len(operation.Responses))
+	for code := range operation.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	var successTypes []string
+	successShapes := make(map[string]string)
+	distinctShapes := make(map[string]bool)
+	errorShapes := make(map[string]string)
+
+	for _, code := range codes {
+		response := operation.Responses[code]
+		contentType, schema := g.responseContent(response)
+		shape := g.codecs.render(schema)
+
+		if isSuccessStatus(code) {
+			responseType := g.adapter.ConvertType(schema)
+			if contentType == "" {
+				responseType = g.adapter.NoContentType()
+			} else {
+				// the lowest successful status with content decides how the response is read
+				if method.ResponseFormat == "" {
+					method.ResponseContentType = contentType
+					method.ResponseFormat = responseFormat(contentType, schema)
+				}
+				distinctShapes[shape] = true
+				if shape != "" {
+					successShapes[code] = shape
+				}
+			}
+			if !utils.Contains(successTypes, responseType) {
+				successTypes = append(successTypes, responseType)
+			}
+			continue
+		}
+
+		errorType := g.adapter.NoContentType()
+		if contentType != "" {
+			errorType = g.adapter.ConvertType(schema)
+		}
+		status := "number"
+		if _, err := strconv.Atoi(code); err == nil {
+			status = code
+		}
+
+		method.Errors = append(method.Errors, models.ResponseModel{
+			StatusCode:  code,
+			Status:      status,
+			Type:        errorType,
+			ContentType: contentType,
+			Description: response.Description,
+		})
+		// every error response is listed, so errors can tell which declared response they match
+		errorShapes[code] = shape
+		if shape == "" {
+			errorShapes[code] = "null"
+		}
+		if code == "default" {
+			method.DefaultError = true
+		}
+	}
+
+	method.ResponseType = g.adapter.ConvertType(nil)
+	if len(successTypes) > 0 {
+		method.ResponseType = g.adapter.JoinTypes(successTypes)
+	}
+
+	// one shape converts every successful response, otherwise the shape is picked by status
+	if len(distinctShapes) == 1 {
+		for _, shape := range successShapes {
+			method.ResponseShape = shape
+		}
+	} else {
+		method.ResponseShapes = renderShapes(successShapes)
+	}
+
+	// binary responses are also offered as file downloads, named by the server
+	if method.ResponseFormat == "binary" {
+		method.DownloadName = method.Name + "File"
+	}
+
+	g.buildResponseHeaders(method, name, operation)
+
+	if len(method.Errors) > 0 {
+		method.ErrorTypeName = g.adapter.FormatTypeName(name + "Error")
+		if _, exists := g.spec.Components.Schemas[name+"Error"]; exists {
+			method.ErrorTypeName = g.adapter.FormatTypeName(name + "ApiError")
+		}
+		method.ErrorShapes = renderShapes(errorShapes)
+	}
+}
+
+// resolveHeader returns the header a response header references, or the header itself
+func (g *ClientGenerator) resolveHeader(header openapi.Header) openapi.Header {
+	if header.Ref != "" {
+		if component, exists := g.spec.Components.Headers[strings.TrimPrefix(header.Ref, "#/components/headers/")]; exists {
+			return component
+		}
+	}
+	return header
+}
+
+// buildHeaderSpec returns how a header of the schema is parsed from its text, or nil for plain strings
+func (g *ClientGenerator) buildHeaderSpec(schema *openapi.Schema) *headerSpec {
+	if schema == nil {
+		return nil
+	}
+	if schema.Ref != "" {
+		if component, exists := g.spec.Components.Schemas[refName(schema.Ref)]; exists {
+			return g.buildHeaderSpec(&component)
+		}
+		return nil
+	}
+
+	if codec := g.adapter.ConvertCodec(schema); codec != "" {
+		return &headerSpec{Type: "string", Codec: codec}
+	}
+
+	switch schema.Type {
+	case "integer", "number", "boolean":
+		return &headerSpec{Type: schema.Type}
+	case "array":
+		spec := &headerSpec{Type: "array"}
+		spec.Items = g.buildHeaderSpec(schema.Items)
+		return spec
+	}
+	return nil
+}
+
+// buildResponseHeaders models the headers of the successful responses of an operation. Headers are named
+// in lower case, as they are received, and only required when every successful response requires them.
+func (g *ClientGenerator) buildResponseHeaders(method *models.MethodModel, name string, operation *openapi.Operation) {
+	headers := make(map[string]openapi.Header)
+	required := make(map[string]int)
+	successes := 0
+
+	for code, response := range operation.Responses {
+		if !isSuccessStatus(code) {
+			continue
+		}
+		successes++
+		for headerName, header := range response.Headers {
+			headerName = strings.ToLower(headerName)
+			// the Content-Type header is described by the content of the response instead
+			if headerName == "content-type" {
+				continue
+			}
+			header = g.resolveHeader(header)
+			if _, exists := headers[headerName]; !exists {
+				headers[headerName] = header
+			}
+			if header.Required {
+				required[headerName]++
+			}
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for headerName := range headers {
+		names = append(names, headerName)
+	}
+	sort.Strings(names)
+
+	specs := make(map[string]*headerSpec)
+	for _, headerName := range names {
+		header := headers[headerName]
+		schema := header.Schema
+		if schema == nil {
+			schema = &openapi.Schema{Type: "string"}
+		}
+
+		method.ResponseHeaders = append(method.ResponseHeaders, models.PropertyModel{
+			Name:     g.adapter.FormatPropertyName(headerName),
+			Type:     g.adapter.ConvertType(schema),
+			Required: required[headerName] == successes,
+			Doc:      models.DocModel{Description: header.Description, Deprecated: header.Deprecated},
+		})
+		if spec := g.buildHeaderSpec(schema); spec != nil {
+			specs[headerName] = spec
+		}
+	}
+
+	if len(method.ResponseHeaders) == 0 {
+		return
+	}
+
+	method.ResponseHeadersTypeName = g.adapter.FormatTypeName(name + "ResponseHeaders")
+	if _, exists := g.spec.Components.Schemas[name+"ResponseHeaders"]; exists {
+		method.ResponseHeadersTypeName = g.adapter.FormatTypeName(name + "ApiResponseHeaders")
+	}
+	if len(specs) > 0 {
+		if data, err := json.Marshal(specs); err == nil {
+			method.ResponseHeaderSpecs = string(data)
+		}
+	}
+}
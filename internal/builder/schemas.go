@@ -0,0 +1,106 @@
+package builder
+
+import (
+	"gogen/internal/openapi"
+)
+
+// cloneSchema returns a deep copy of a schema, so passes can rewrite it without touching the spec
+func cloneSchema(schema *openapi.Schema) *openapi.Schema {
+	if schema == nil {
+		return nil
+	}
+
+	clone := *schema
+	clone.Types = append([]string(nil), schema.Types...)
+	clone.Enum = append([]any(nil), schema.Enum...)
+	clone.Items = cloneSchema(schema.Items)
+	clone.AllOf = cloneSchemas(schema.AllOf)
+	clone.OneOf = cloneSchemas(schema.OneOf)
+	clone.AnyOf = cloneSchemas(schema.AnyOf)
+
+	if schema.Properties != nil {
+		clone.Properties = make(map[string]*openapi.Schema, len(schema.Properties))
+		for name, property := range schema.Properties {
+			clone.Properties[name] = cloneSchema(property)
+		}
+	}
+
+	if schema.AdditionalProperties != nil {
+		additional := *schema.AdditionalProperties
+		additional.SchemaValue = cloneSchema(schema.AdditionalProperties.SchemaValue)
+		clone.AdditionalProperties = &additional
+	}
+
+	if schema.Required != nil {
+		required := *schema.Required
+		required.ArrayValue = append([]string(nil), schema.Required.ArrayValue...)
+		clone.Required = &required
+	}
+
+	if schema.Discriminator != nil {
+		discriminator := *schema.Discriminator
+		discriminator.Mapping = make(map[string]string, len(schema.Discriminator.Mapping))
+		for value, ref := range schema.Discriminator.Mapping {
+			discriminator.Mapping[value] = ref
+		}
+		clone.Discriminator = &discriminator
+	}
+
+	return &clone
+}
+
+func cloneSchemas(schemas []openapi.Schema) []openapi.Schema {
+	if schemas == nil {
+		return nil
+	}
+
+	clones := make([]openapi.Schema, len(schemas))
+	for i := range schemas {
+		clones[i] = *cloneSchema(&schemas[i])
+	}
+	return clones
+}
+
+// walkSchema calls visit for the schema and every inline subschema, parents before children.
+// References are not followed, so the walk always terminates.
+func walkSchema(schema *openapi.Schema, visit func(*openapi.Schema)) {
+	if schema == nil {
+		return
+	}
+
+	visit(schema)
+
+	for _, property := range schema.Properties {
+		walkSchema(property, visit)
+	}
+	walkSchema(schema.Items, visit)
+	if schema.AdditionalProperties != nil {
+		walkSchema(schema.AdditionalProperties.SchemaValue, visit)
+	}
+	for _, subSchemas := range [][]openapi.Schema{schema.AllOf, schema.OneOf, schema.AnyOf} {
+		for i := range subSchemas {
+			walkSchema(&subSchemas[i], visit)
+		}
+	}
+}
+
+// schemaRefs lists the component schemas a schema references directly or through inline subschemas
+func schemaRefs(schema *openapi.Schema) []string {
+	var refs []string
+	walkSchema(schema, func(s *openapi.Schema) {
+		if s.Ref != "" {
+			refs = append(refs, refName(s.Ref))
+		}
+		if s.Discriminator != nil {
+			for _, ref := range s.Discriminator.Mapping {
+				refs = append(refs, refName(ref))
+			}
+		}
+	})
+	return refs
+}
+
+// componentRef returns the reference to a component schema
+func componentRef(name string) string {
+	return "#/components/schemas/" + name
+}
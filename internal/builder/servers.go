@@ -0,0 +1,137 @@
+package builder
+
+import (
+	"encoding/json"
+	"gogen/internal/models"
+	"gogen/internal/openapi"
+	"gogen/internal/utils"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// serverSpec is the language-neutral form of a server, rendered as JSON. The runtime substitutes the
+// variables in braces in the URL, falling back to their defaults.
+type serverSpec struct {
+	URL       string                        `json:"url"`
+	Variables map[string]serverVariableSpec `json:"variables,omitempty"`
+}
+
+type serverVariableSpec struct {
+	Default string   `json:"default"`
+	Enum    []string `json:"enum,omitempty"`
+}
+
+// resolveServerURL substitutes the defaults of the variables in the URL of a server
+func resolveServerURL(server openapi.Server) string {
+	url := server.URL
+	for name, variable := range server.Variables {
+		url = strings.ReplaceAll(url, "{"+name+"}", variable.Default)
+	}
+	return url
+}
+
+// buildServer models a server, with the variables in its URL sorted by name
+func (g *ClientGenerator) buildServer(name string, server openapi.Server) models.ServerModel {
+	model := models.ServerModel{
+		Name:        name,
+		URL:         server.URL,
+		Description: server.Description,
+	}
+
+	names := make([]string, 0, len(server.Variables))
+	for variableName := range server.Variables {
+		names = append(names, variableName)
+	}
+	sort.Strings(names)
+
+	spec := serverSpec{URL: server.URL}
+	if len(names) > 0 {
+		spec.Variables = make(map[string]serverVariableSpec, len(names))
+	}
+	for _, variableName := range names {
+		variable := server.Variables[variableName]
+		spec.Variables[variableName] = serverVariableSpec{Default: variable.Default, Enum: variable.Enum}
+
+		variableType := g.adapter.ConvertType(&openapi.Schema{Type: "string"})
+		if len(variable.Enum) > 0 {
+			variableType = g.literalType(variable.Enum)
+		}
+		model.Variables = append(model.Variables, models.PropertyModel{
+			Name: g.adapter.FormatPropertyName(variableName),
+			Type: variableType,
+			Doc:  models.DocModel{Description: variable.Description, Default: docValue(variable.Default)},
+		})
+	}
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		data = []byte(strconv.Quote(server.URL))
+	}
+	model.Spec = string(data)
+	return model
+}
+
+// buildServers models the servers of the spec, named after their descriptions, or by position when they
+// have none
+func (g *ClientGenerator) buildServers() []models.ServerModel {
+	var servers []models.ServerModel
+	used := make(map[string]bool)
+
+	for i, server := range g.spec.Servers {
+		name := utils.ToCamelCase(server.Description)
+		if name == "" {
+			name = "server" + strconv.Itoa(i+1)
+		}
+		for base, suffix := name, 2; used[name]; suffix++ {
+			name = base + strconv.Itoa(suffix)
+		}
+		used[name] = true
+
+		servers = append(servers, g.buildServer(name, server))
+	}
+
+	return servers
+}
+
+// buildOperationServer models the server an operation is sent to when the operation or its path override
+// the servers of the spec. The first of the overriding servers is used. Operations sharing a server share
+// its entry in operationServers, named after its description or the first method sent to it, so the
+// variables in its URL are configured once and apart from those of the servers of the spec.
+func (g *ClientGenerator) buildOperationServer(path, methodName string, operation *openapi.Operation) *models.ServerModel {
+	servers := operation.Servers
+	if len(servers) == 0 {
+		servers = g.spec.Paths[path].Servers
+	}
+	if len(servers) == 0 {
+		return nil
+	}
+
+	server := g.buildServer("", servers[0])
+	for i := range g.operationServers {
+		if g.operationServers[i].Spec == server.Spec {
+			return &g.operationServers[i]
+		}
+	}
+
+	name := utils.ToCamelCase(server.Description)
+	if name == "" {
+		name = methodName + "Server"
+	}
+	for base, suffix := name, 2; g.hasOperationServer(name); suffix++ {
+		name = base + strconv.Itoa(suffix)
+	}
+	server.Name = name
+
+	g.operationServers = append(g.operationServers, server)
+	return &server
+}
+
+func (g *ClientGenerator) hasOperationServer(name string) bool {
+	for _, server := range g.operationServers {
+		if server.Name == name {
+			return true
+		}
+	}
+	return false
+}
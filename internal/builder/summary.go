@@ -0,0 +1,83 @@
+package builder
+
+import (
+	"fmt"
+	"gogen/internal/models"
+	"gogen/internal/openapi"
+	"strings"
+)
+
+// countOf renders a count with its noun, e.g. "1 method" or "3 types"
+func countOf(count int, noun string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, noun)
+	}
+	if strings.HasSuffix(noun, "y") {
+		return fmt.Sprintf("%d %sies", count, strings.TrimSuffix(noun, "y"))
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
+
+// countDeprecations counts the deprecated component schemas and the deprecated properties they declare,
+// before read/write variants repeat them
+func (g *ClientGenerator) countDeprecations() {
+	for _, schema := range g.spec.Components.Schemas {
+		if schema.Deprecated {
+			g.deprecatedSchemas++
+		}
+		walkSchema(&schema, func(s *openapi.Schema) {
+			for _, property := range s.Properties {
+				if property != nil && property.Deprecated {
+					g.deprecatedProperties++
+				}
+			}
+		})
+	}
+}
+
+// buildSummary describes the generated code: the number of methods, types and webhooks, how many of them
+// are deprecated, and the deprecated operations left out
+func (g *ClientGenerator) buildSummary(model *models.ClientModel) string {
+	var deprecatedMethods, deprecatedWebhooks int
+	for _, method := range model.Methods {
+		if method.Doc.Deprecated {
+			deprecatedMethods++
+		}
+	}
+	for _, webhook := range model.Webhooks {
+		if webhook.Doc.Deprecated {
+			deprecatedWebhooks++
+		}
+	}
+
+	generated := []string{countOf(len(model.Methods), "method"), countOf(len(model.Types), "type")}
+	if len(model.Webhooks) > 0 {
+		generated = append(generated, countOf(len(model.Webhooks), "webhook"))
+	}
+	lines := []string{"Generated " + strings.Join(generated, ", ")}
+
+	var deprecated []string
+	for _, part := range []struct {
+		count int
+		noun  string
+	}{
+		{deprecatedMethods, "method"},
+		{g.deprecatedSchemas, "type"},
+		{g.deprecatedProperties, "property"},
+		{deprecatedWebhooks, "webhook"},
+	} {
+		if part.count > 0 {
+			deprecated = append(deprecated, countOf(part.count, part.noun))
+		}
+	}
+	if len(deprecated) > 0 {
+		lines = append(lines, "Deprecated: "+strings.Join(deprecated, ", "))
+	}
+
+	if len(g.excluded) > 0 {
+		lines = append(lines, fmt.Sprintf("Excluded %s: %s",
+			countOf(len(g.excluded), "deprecated operation"), strings.Join(g.excluded, ", ")))
+	}
+
+	return strings.Join(lines, "\n")
+}
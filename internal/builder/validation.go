@@ -0,0 +1,171 @@
+package builder
+
+import (
+	"encoding/json"
+	"gogen/internal/models"
+	"gogen/internal/openapi"
+	"sort"
+)
+
+// validationRule is the language-neutral form of the constraints of a schema. It is rendered into the
+// generated validation module as JSON, referencing the rules of component schemas by name.
+type validationRule struct {
+	Ref              string                     `json:"ref,omitempty"`
+	Types            []string                   `json:"types,omitempty"`
+	Nullable         bool                       `json:"nullable,omitempty"`
+	Enum             []any                      `json:"enum,omitempty"`
+	Format           string                     `json:"format,omitempty"`
+	MinLength        *int                       `json:"minLength,omitempty"`
+	MaxLength        *int                       `json:"maxLength,omitempty"`
+	Pattern          string                     `json:"pattern,omitempty"`
+	Minimum          *float64                   `json:"minimum,omitempty"`
+	Maximum          *float64                   `json:"maximum,omitempty"`
+	ExclusiveMinimum *float64                   `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64                   `json:"exclusiveMaximum,omitempty"`
+	MultipleOf       *float64                   `json:"multipleOf,omitempty"`
+	Items            *validationRule            `json:"items,omitempty"`
+	MinItems         *int                       `json:"minItems,omitempty"`
+	MaxItems         *int                       `json:"maxItems,omitempty"`
+	UniqueItems      bool                       `json:"uniqueItems,omitempty"`
+	Properties       map[string]*validationRule `json:"properties,omitempty"`
+	Required         []string                   `json:"required,omitempty"`
+	// Additional is false when no extra properties are allowed, or the rule extra properties must satisfy
+	Additional    any                        `json:"additionalProperties,omitempty"`
+	MinProperties *int                       `json:"minProperties,omitempty"`
+	MaxProperties *int                       `json:"maxProperties,omitempty"`
+	AllOf         []*validationRule          `json:"allOf,omitempty"`
+	AnyOf         []*validationRule          `json:"anyOf,omitempty"`
+	Discriminator string                     `json:"discriminator,omitempty"`
+	Mapping       map[string]*validationRule `json:"mapping,omitempty"`
+	// Codec is set to "integer-string" for integers generated as strings, which are checked as integers
+	Codec string `json:"codec,omitempty"`
+}
+
+// buildRule converts the constraints of a schema into a validation rule
+func (g *ClientGenerator) buildRule(schema *openapi.Schema) *validationRule {
+	if schema == nil {
+		return &validationRule{}
+	}
+
+	if schema.Ref != "" {
+		return &validationRule{Ref: refName(schema.Ref), Nullable: schema.Nullable}
+	}
+
+	rule := &validationRule{
+		Nullable:      schema.Nullable,
+		Enum:          schema.Enum,
+		Format:        schema.Format,
+		MinLength:     schema.MinLength,
+		MaxLength:     schema.MaxLength,
+		Pattern:       schema.Pattern,
+		Minimum:       schema.Minimum,
+		Maximum:       schema.Maximum,
+		MultipleOf:    schema.MultipleOf,
+		MinItems:      schema.MinItems,
+		MaxItems:      schema.MaxItems,
+		UniqueItems:   schema.UniqueItems,
+		MinProperties: schema.MinProperties,
+		MaxProperties: schema.MaxProperties,
+	}
+	if g.adapter.ConvertCodec(schema) == "integer-string" {
+		rule.Codec = "integer-string"
+	}
+
+	switch {
+	case len(schema.Types) > 0:
+		rule.Types = schema.Types
+	case schema.Type != "":
+		rule.Types = []string{schema.Type}
+	}
+
+	// 3.0 flags make minimum and maximum exclusive, 3.1 gives the exclusive bounds themselves
+	if bound := schema.ExclusiveMinimum; bound != nil {
+		if bound.NumberValue != nil {
+			rule.ExclusiveMinimum = bound.NumberValue
+		} else if bound.BoolValue != nil && *bound.BoolValue {
+			rule.ExclusiveMinimum, rule.Minimum = schema.Minimum, nil
+		}
+	}
+	if bound := schema.ExclusiveMaximum; bound != nil {
+		if bound.NumberValue != nil {
+			rule.ExclusiveMaximum = bound.NumberValue
+		} else if bound.BoolValue != nil && *bound.BoolValue {
+			rule.ExclusiveMaximum, rule.Maximum = schema.Maximum, nil
+		}
+	}
+
+	if schema.Items != nil {
+		rule.Items = g.buildRule(schema.Items)
+	}
+
+	if len(schema.Properties) > 0 {
+		rule.Properties = make(map[string]*validationRule, len(schema.Properties))
+		for name, property := range schema.Properties {
+			rule.Properties[name] = g.buildRule(property)
+			if schema.IsRequired(name) {
+				rule.Required = append(rule.Required, name)
+			}
+		}
+		sort.Strings(rule.Required)
+	}
+
+	if additional := schema.AdditionalProperties; additional != nil {
+		if !additional.Allowed() {
+			rule.Additional = false
+		} else if additional.SchemaValue != nil {
+			rule.Additional = g.buildRule(additional.SchemaValue)
+		}
+	}
+
+	for i := range schema.AllOf {
+		rule.AllOf = append(rule.AllOf, g.buildRule(&schema.AllOf[i]))
+	}
+
+	// variants of generated union types often overlap, so oneOf is checked like anyOf; discriminated
+	// unions check the variant selected by the discriminator
+	variants := append(append([]openapi.Schema{}, schema.OneOf...), schema.AnyOf...)
+	if len(variants) > 0 && schema.Discriminator != nil && schema.Discriminator.PropertyName != "" {
+		rule.Discriminator = schema.Discriminator.PropertyName
+		rule.Mapping = make(map[string]*validationRule)
+		for _, variant := range discriminatorVariants(schema) {
+			for _, value := range variant.values {
+				rule.Mapping[value] = &validationRule{Ref: variant.schemaName}
+			}
+		}
+	} else {
+		for i := range variants {
+			rule.AnyOf = append(rule.AnyOf, g.buildRule(&variants[i]))
+		}
+	}
+
+	return rule
+}
+
+// renderRule renders the validation rule of a schema as JSON
+func (g *ClientGenerator) renderRule(schema *openapi.Schema) string {
+	data, err := json.Marshal(g.buildRule(schema))
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
+
+// buildValidators renders the validation rules of all component schemas
+func (g *ClientGenerator) buildValidators() []models.ValidatorModel {
+	names := make([]string, 0, len(g.spec.Components.Schemas))
+	for name := range g.spec.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var validators []models.ValidatorModel
+	for _, name := range names {
+		schema := g.spec.Components.Schemas[name]
+		validators = append(validators, models.ValidatorModel{
+			Name:     name,
+			TypeName: g.adapter.FormatTypeName(name),
+			Rule:     g.renderRule(&schema),
+		})
+	}
+	return validators
+}
@@ -0,0 +1,170 @@
+package builder
+
+import (
+	"gogen/internal/models"
+	"gogen/internal/openapi"
+	"gogen/internal/utils"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// webhookOperation is an operation the API sends to a server of its consumers, named after the webhook,
+// or after the operation and callback it belongs to
+type webhookOperation struct {
+	typeName   string
+	kind       string
+	key        string
+	httpMethod string
+	operation  *openapi.Operation
+	// sender is the operation sending a callback to the URL given by expression
+	sender     *openapi.Operation
+	senderVerb string
+	expression string
+}
+
+// resolveCallback follows a reference to one of the callback components
+func (g *ClientGenerator) resolveCallback(callback openapi.Callback) openapi.Callback {
+	if callback.Ref != "" {
+		return g.spec.Components.Callbacks[strings.TrimPrefix(callback.Ref, "#/components/callbacks/")]
+	}
+	return callback
+}
+
+// webhookOperations lists the webhooks and the callbacks of the operations in a deterministic order. The
+// HTTP method is appended to the type names of webhooks and callbacks sending more than one operation.
+func (g *ClientGenerator) webhookOperations() []webhookOperation {
+	var webhooks []webhookOperation
+	used := make(map[string]bool)
+
+	add := func(base string, multiple bool, webhook webhookOperation) {
+		name := base
+		if multiple {
+			name += utils.ToPascalIdentifier(strings.ToLower(webhook.httpMethod))
+		}
+		for unique, suffix := name, 2; used[name]; suffix++ {
+			name = unique + strconv.Itoa(suffix)
+		}
+		used[name] = true
+
+		webhook.typeName = name
+		webhooks = append(webhooks, webhook)
+	}
+
+	names := make([]string, 0, len(g.spec.Webhooks))
+	for name := range g.spec.Webhooks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		operations := pathOperations(g.spec.Webhooks[name])
+		count := countOperations(operations)
+		for _, httpMethod := range httpMethods {
+			if operation := operations[httpMethod]; operation != nil && !g.isExcluded(operation) {
+				add(utils.ToPascalIdentifier(name), count > 1, webhookOperation{
+					kind:       "webhook",
+					key:        name,
+					httpMethod: httpMethod,
+					operation:  operation,
+				})
+			}
+		}
+	}
+
+	for _, path := range sortedPaths(g.spec.Paths) {
+		senders := pathOperations(g.spec.Paths[path])
+		for _, senderVerb := range httpMethods {
+			sender := senders[senderVerb]
+			if sender == nil || len(sender.Callbacks) == 0 || g.isExcluded(sender) {
+				continue
+			}
+
+			keys := make([]string, 0, len(sender.Callbacks))
+			for key := range sender.Callbacks {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				callback := g.resolveCallback(sender.Callbacks[key])
+				base := operationTypeName(path, senderVerb, sender) + utils.ToPascalIdentifier(key)
+
+				count := 0
+				for _, pathItem := range callback.Paths {
+					count += countOperations(pathOperations(pathItem))
+				}
+
+				for _, expression := range sortedPaths(callback.Paths) {
+					operations := pathOperations(callback.Paths[expression])
+					for _, httpMethod := range httpMethods {
+						if operation := operations[httpMethod]; operation != nil && !g.isExcluded(operation) {
+							add(base, count > 1, webhookOperation{
+								kind:       "callback",
+								key:        key,
+								httpMethod: httpMethod,
+								operation:  operation,
+								sender:     sender,
+								senderVerb: senderVerb,
+								expression: expression,
+							})
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return webhooks
+}
+
+// countOperations counts the operations of a path item
+func countOperations(operations map[string]*openapi.Operation) int {
+	count := 0
+	for _, operation := range operations {
+		if operation != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// buildWebhooks models the webhooks and callbacks. The payload is the request body the API sends, read
+// like a response, and the response type is what the handler of the consumer answers with.
+func (g *ClientGenerator) buildWebhooks() []models.WebhookModel {
+	var webhooks []models.WebhookModel
+
+	for _, webhook := range g.webhookOperations() {
+		operation := webhook.operation
+		model := models.WebhookModel{
+			Name:        utils.ToIdentifier(webhook.typeName),
+			TypeName:    webhook.typeName,
+			Kind:        webhook.kind,
+			Key:         webhook.key,
+			HTTPMethod:  webhook.httpMethod,
+			Expression:  webhook.expression,
+			Doc:         models.DocModel{Title: operation.Summary, Description: operation.Description, Deprecated: operation.Deprecated},
+			PayloadType: g.adapter.NoContentType(),
+		}
+		if webhook.sender != nil {
+			model.Operation = g.adapter.FormatMethodName(webhook.sender.OperationID, webhook.senderVerb, webhook.sender.Tags)
+		}
+
+		if operation.RequestBody != nil && len(operation.RequestBody.Content) > 0 {
+			contentType, schema := g.responseContent(openapi.Response{Content: operation.RequestBody.Content})
+			model.PayloadType = g.adapter.ConvertType(schema)
+			model.PayloadFormat = responseFormat(contentType, schema)
+			if model.PayloadFormat == "json" {
+				model.PayloadShape = g.codecs.render(schema)
+			}
+		}
+
+		var responses models.MethodModel
+		g.buildResponses(&responses, webhook.typeName, operation)
+		model.ResponseType = responses.ResponseType
+
+		webhooks = append(webhooks, model)
+	}
+
+	return webhooks
+}
@@ -2,13 +2,73 @@ package models
 
 // ClientModel represents the complete client model for code generation
 type ClientModel struct {
-	ProjectName  string
-	Description  string
-	Version      string
-	BaseURL      string
-	Methods      []MethodModel
-	Types        []TypeModel
-	Dependencies []string
+	ProjectName string
+	Description string
+	Version     string
+	BaseURL     string
+	Servers     []ServerModel
+	// OperationServers are the servers that operations or their paths override Servers with
+	OperationServers []ServerModel
+	Methods          []MethodModel
+	Types            []TypeModel
+	SecuritySchemes  []SecuritySchemeModel
+	Dependencies     []string
+	// EnumStyle selects how enums are generated: "union", "const" or "enum"
+	EnumStyle string
+	// ResponseMode selects what methods return: "data", an "envelope" with status and headers, or "both"
+	ResponseMode string
+	// Shapes describe the component types with values converted by codecs, Codecs lists the codecs in use
+	Shapes []ShapeModel
+	Codecs []string
+	// Validation enables the validation module, Validators holds the rendered rules of the component types
+	Validation bool
+	Validators []ValidatorModel
+	// Webhooks lists the webhooks and callbacks the API sends to the servers of its consumers
+	Webhooks []WebhookModel
+}
+
+// ShapeModel is the rendered codec shape of a component type
+type ShapeModel struct {
+	Name  string
+	Shape string
+}
+
+// ServerModel represents a server the API is available at. The URL may hold variables in braces, and Spec
+// is the rendered URL with the defaults and allowed values of the variables.
+type ServerModel struct {
+	Name        string
+	URL         string
+	Description string
+	Variables   []PropertyModel
+	Spec        string
+}
+
+// WebhookModel represents a request the API sends to a server of its consumers: a webhook, or a callback
+// of an operation to the URL given by a runtime expression
+type WebhookModel struct {
+	Name     string
+	TypeName string
+	// Kind is "webhook" or "callback"; Key is the name of the webhook or callback
+	Kind       string
+	Key        string
+	HTTPMethod string
+	// Operation and Expression are the method sending a callback and the expression giving its URL
+	Operation  string
+	Expression string
+	Doc        DocModel
+	// PayloadFormat tells how the payload is read: "json", "text", "binary" or empty without content;
+	// PayloadShape is its rendered codec shape
+	PayloadType   string
+	PayloadFormat string
+	PayloadShape  string
+	ResponseType  string
+}
+
+// ValidatorModel is the rendered validation rule of a component type
+type ValidatorModel struct {
+	Name     string
+	TypeName string
+	Rule     string
 }
 
 // MethodModel represents a single API method
@@ -18,36 +78,164 @@ type MethodModel struct {
 	Path         string
 	Summary      string
 	Description  string
+	Doc          DocModel
 	Parameters   []ParameterModel
+	PathParams   []ParameterModel
+	QueryParams  []ParameterModel
+	HeaderParams []ParameterModel
+	CookieParams []ParameterModel
 	RequestBody  *RequestBodyModel
+	Security     []SecurityRequirementModel
 	ResponseType string
+	// ResponseShape is the rendered codec shape of the response, empty when nothing needs converting
+	ResponseShape string
+	// ResponseFormat tells how the response is read: "json", "text", "binary" or empty without content
+	ResponseContentType string
+	ResponseFormat      string
+	// DownloadName names the method returning a binary response as a file download
+	DownloadName string
+	// ResponseShapes is the rendered map of codec shapes by status code, set instead of ResponseShape when
+	// the successful responses need different conversions
+	ResponseShapes string
+	// Errors lists the error responses, thrown as the type named by ErrorTypeName; ErrorShapes is the
+	// rendered map of their codec shapes by status code, null for bodies used as they are. DefaultError is
+	// set when a default response covers the statuses not declared.
+	Errors        []ResponseModel
+	ErrorTypeName string
+	ErrorShapes   string
+	DefaultError  bool
+	// ResponseHeaders lists the headers of the successful responses, typed by the type named by
+	// ResponseHeadersTypeName; ResponseHeaderSpecs is the rendered map of the headers parsed from text
+	ResponseHeaders         []PropertyModel
+	ResponseHeadersTypeName string
+	ResponseHeaderSpecs     string
+	// Server is the entry of OperationServers overriding the servers of the spec for the method, if any
+	Server *ServerModel
 }
 
-// ParameterModel represents a method parameter
-type ParameterModel struct {
-	Name        string
+// ResponseModel represents a response of a method by status code, e.g. "404", "4XX" or "default".
+// Status is the literal type of the status code, or a number type for ranges and the default response.
+type ResponseModel struct {
+	StatusCode  string
+	Status      string
 	Type        string
-	In          string
-	Required    bool
+	ContentType string
 	Description string
 }
 
+// ParameterModel represents a method parameter
+type ParameterModel struct {
+	Name          string
+	WireName      string
+	Type          string
+	In            string
+	Required      bool
+	Description   string
+	Style         string
+	Explode       bool
+	AllowReserved bool
+	ContentType   string
+	Shape         string
+}
+
 // RequestBodyModel represents a request body
 type RequestBodyModel struct {
 	Type     string
 	Required bool
+	// ContentType is the preferred content type, sent unless the caller picks another one through the
+	// parameter named by ContentTypeParam, which is only set when several content types are declared
+	ContentType      string
+	ContentTypeParam string
+	Contents         []RequestContentModel
+	// Rule is the rendered validation rule of the body, empty when validation is disabled
+	Rule string
+}
+
+// RequestContentModel represents a content type a request body can be sent as. Encoding is the rendered
+// encoding of the properties of multipart and form bodies.
+type RequestContentModel struct {
+	ContentType string
+	Type        string
+	Shape       string
+	Encoding    string
 }
 
 // TypeModel represents a data type/schema
 type TypeModel struct {
-	Name       string
-	Type       string
-	Properties []PropertyModel
+	Name string
+	// Kind tells how the type is declared: "object", "alias", "enum", "union", "intersection" or "map"
+	Kind          string
+	Type          string
+	Properties    []PropertyModel
+	Discriminator *DiscriminatorModel
+	// IndexSignatureType is the value type of extra properties allowed next to the fixed ones
+	IndexSignatureType string
+	// Extends lists the types an object type inherits the properties of
+	Extends    []string
+	EnumValues []EnumValueModel
+	Nullable   bool
+	Doc        DocModel
+}
+
+// EnumValueModel represents a member of an enum type
+type EnumValueModel struct {
+	Name        string
+	Value       string
+	Description string
+}
+
+// DiscriminatorModel represents a union whose variants are told apart by the value of a property
+type DiscriminatorModel struct {
+	PropertyName string
+	Variants     []DiscriminatorVariantModel
 }
 
-// PropertyModel represents a property of a type
+// DiscriminatorVariantModel represents a variant of a discriminated union
+type DiscriminatorVariantModel struct {
+	TypeName  string
+	GuardName string
+	Values    []string
+	// ValueType is the literal type of the discriminator values
+	ValueType string
+	// Tagged is set when the variant type does not declare the discriminator property itself
+	Tagged bool
+}
+
+// PropertyModel represents a property of a type. Required properties must be present; the type of
+// nullable ones includes null.
 type PropertyModel struct {
 	Name     string
 	Type     string
 	Required bool
+	ReadOnly bool
+	Doc      DocModel
+}
+
+// DocModel holds the documentation of a type, property or method. Default and Example are rendered as JSON.
+type DocModel struct {
+	Title       string
+	Description string
+	Default     string
+	Example     string
+	Deprecated  bool
+}
+
+// SecuritySchemeModel represents a security scheme the client can authenticate with
+type SecuritySchemeModel struct {
+	Name             string
+	Identifier       string
+	Type             string
+	Description      string
+	Scheme           string
+	In               string
+	ParamName        string
+	BearerFormat     string
+	OpenIDConnectURL string
+	TokenURL         string
+	RefreshURL       string
+}
+
+// SecurityRequirementModel represents a set of security schemes that must all be applied to a request
+type SecurityRequirementModel struct {
+	Schemes []string
 }
@@ -7,10 +7,13 @@ import (
 
 // OpenAPISpec represents the root OpenAPI specification
 type OpenAPISpec struct {
-	Info       Info                `json:"info"`
-	Paths      map[string]PathItem `json:"paths"`
-	Components Components          `json:"components"`
-	Servers    []Server            `json:"servers"`
+	Info       Info                  `json:"info"`
+	Paths      map[string]PathItem   `json:"paths"`
+	Components Components            `json:"components"`
+	Servers    []Server              `json:"servers"`
+	Security   []SecurityRequirement `json:"security"`
+	// Webhooks are the 3.1 requests the API sends to the servers of its consumers, by name
+	Webhooks map[string]PathItem `json:"webhooks,omitempty"`
 }
 
 // Info contains metadata about the API
@@ -22,8 +25,16 @@ type Info struct {
 
 // Server represents a server configuration
 type Server struct {
-	URL         string `json:"url"`
-	Description string `json:"description"`
+	URL         string                    `json:"url"`
+	Description string                    `json:"description"`
+	Variables   map[string]ServerVariable `json:"variables,omitempty"`
+}
+
+// ServerVariable describes a variable substituted in a server URL
+type ServerVariable struct {
+	Enum        []string `json:"enum,omitempty"`
+	Default     string   `json:"default"`
+	Description string   `json:"description"`
 }
 
 // PathItem describes the operations available on a single path
@@ -33,6 +44,8 @@ type PathItem struct {
 	Put    *Operation `json:"put,omitempty"`
 	Delete *Operation `json:"delete,omitempty"`
 	Patch  *Operation `json:"patch,omitempty"`
+	// Servers override the servers of the spec for the operations on the path
+	Servers []Server `json:"servers,omitempty"`
 }
 
 // Operation describes a single API operation on a path
@@ -44,15 +57,45 @@ type Operation struct {
 	RequestBody *RequestBody        `json:"requestBody,omitempty"`
 	Responses   map[string]Response `json:"responses"`
 	Tags        []string            `json:"tags"`
+	Deprecated  bool                `json:"deprecated"`
+	// Security is nil when the operation inherits the global requirements, and empty when it requires no auth
+	Security *[]SecurityRequirement `json:"security,omitempty"`
+	// Servers override the servers of the spec and the path for the operation
+	Servers []Server `json:"servers,omitempty"`
+	// Callbacks are the requests the API sends in response to the operation, by name
+	Callbacks map[string]Callback `json:"callbacks,omitempty"`
+}
+
+// Callback maps runtime expressions, which give the URLs the API sends requests to, to the operations
+// sent there; or references one of the components
+type Callback struct {
+	Ref   string
+	Paths map[string]PathItem
+}
+
+func (c *Callback) UnmarshalJSON(data []byte) error {
+	var ref struct {
+		Ref string `json:"$ref"`
+	}
+	if err := json.Unmarshal(data, &ref); err == nil && ref.Ref != "" {
+		c.Ref = ref.Ref
+		return nil
+	}
+
+	return json.Unmarshal(data, &c.Paths)
 }
 
 // Parameter describes a single operation parameter
 type Parameter struct {
-	Name        string  `json:"name"`
-	In          string  `json:"in"`
-	Required    bool    `json:"required"`
-	Description string  `json:"description"`
-	Schema      *Schema `json:"schema"`
+	Name          string               `json:"name"`
+	In            string               `json:"in"`
+	Required      bool                 `json:"required"`
+	Description   string               `json:"description"`
+	Schema        *Schema              `json:"schema"`
+	Style         string               `json:"style,omitempty"`
+	Explode       *bool                `json:"explode,omitempty"`
+	AllowReserved bool                 `json:"allowReserved"`
+	Content       map[string]MediaType `json:"content,omitempty"`
 }
 
 // RequestBody describes a single request body
@@ -64,17 +107,71 @@ type RequestBody struct {
 // Response describes a single response from an API Operation
 type Response struct {
 	Description string               `json:"description"`
+	Headers     map[string]Header    `json:"headers,omitempty"`
 	Content     map[string]MediaType `json:"content"`
 }
 
+// Header describes a response header, or references one of the components
+type Header struct {
+	Ref         string               `json:"$ref,omitempty"`
+	Description string               `json:"description"`
+	Required    bool                 `json:"required"`
+	Deprecated  bool                 `json:"deprecated"`
+	Schema      *Schema              `json:"schema"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
 // MediaType provides schema and examples for the media type identified by its key
 type MediaType struct {
-	Schema *Schema `json:"schema"`
+	Schema   *Schema             `json:"schema"`
+	Encoding map[string]Encoding `json:"encoding,omitempty"`
+}
+
+// Encoding describes how a property of a multipart or form request body is serialized
+type Encoding struct {
+	ContentType   string `json:"contentType,omitempty"`
+	Style         string `json:"style,omitempty"`
+	Explode       *bool  `json:"explode,omitempty"`
+	AllowReserved bool   `json:"allowReserved,omitempty"`
 }
 
 // Components holds a set of reusable objects for different aspects of the OAS
 type Components struct {
-	Schemas map[string]Schema `json:"schemas"`
+	Schemas         map[string]Schema         `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+	Headers         map[string]Header         `json:"headers"`
+	Callbacks       map[string]Callback       `json:"callbacks"`
+}
+
+// SecurityRequirement lists the security schemes, with their required scopes, that must all be satisfied
+type SecurityRequirement map[string][]string
+
+// SecurityScheme defines a security scheme that can be used by the operations
+type SecurityScheme struct {
+	Type             string      `json:"type"`
+	Description      string      `json:"description"`
+	Name             string      `json:"name"`
+	In               string      `json:"in"`
+	Scheme           string      `json:"scheme"`
+	BearerFormat     string      `json:"bearerFormat"`
+	Flows            *OAuthFlows `json:"flows,omitempty"`
+	OpenIDConnectURL string      `json:"openIdConnectUrl"`
+}
+
+// OAuthFlows allows configuration of the supported OAuth flows
+type OAuthFlows struct {
+	Implicit          *OAuthFlow `json:"implicit,omitempty"`
+	Password          *OAuthFlow `json:"password,omitempty"`
+	ClientCredentials *OAuthFlow `json:"clientCredentials,omitempty"`
+	AuthorizationCode *OAuthFlow `json:"authorizationCode,omitempty"`
+}
+
+// OAuthFlow holds configuration details for a supported OAuth flow
+type OAuthFlow struct {
+	AuthorizationURL string            `json:"authorizationUrl"`
+	TokenURL         string            `json:"tokenUrl"`
+	RefreshURL       string            `json:"refreshUrl"`
+	Scopes           map[string]string `json:"scopes"`
 }
 
 // FlexibleRequired is a flexible required field that can be either a boolean or an array of strings
@@ -100,17 +197,191 @@ func (f *FlexibleRequired) UnmarshalJSON(data []byte) error {
 	return fmt.Errorf("failed to unmarshal required field")
 }
 
+// AdditionalProperties is either a boolean allowing or forbidding extra properties,
+// or a schema the values of extra properties must match
+type AdditionalProperties struct {
+	BoolValue   *bool   `json:"boolValue,omitempty"`
+	SchemaValue *Schema `json:"schemaValue,omitempty"`
+}
+
+func (a *AdditionalProperties) UnmarshalJSON(data []byte) error {
+	var temp bool
+	if err := json.Unmarshal(data, &temp); err == nil {
+		a.BoolValue = &temp
+		return nil
+	}
+
+	var schema Schema
+	if err := json.Unmarshal(data, &schema); err == nil {
+		a.SchemaValue = &schema
+		return nil
+	}
+
+	return fmt.Errorf("failed to unmarshal additionalProperties field")
+}
+
+// Allowed checks if extra properties are allowed, which is the default
+func (a *AdditionalProperties) Allowed() bool {
+	return a == nil || a.SchemaValue != nil || a.BoolValue == nil || *a.BoolValue
+}
+
+// ExclusiveBound is either the 3.0 boolean making minimum or maximum exclusive, or the 3.1 exclusive bound itself
+type ExclusiveBound struct {
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	NumberValue *float64 `json:"numberValue,omitempty"`
+}
+
+func (e *ExclusiveBound) UnmarshalJSON(data []byte) error {
+	var temp bool
+	if err := json.Unmarshal(data, &temp); err == nil {
+		e.BoolValue = &temp
+		return nil
+	}
+
+	var number float64
+	if err := json.Unmarshal(data, &number); err == nil {
+		e.NumberValue = &number
+		return nil
+	}
+
+	return fmt.Errorf("failed to unmarshal exclusive bound field")
+}
+
 // Schema allows the definition of input and output data types
 type Schema struct {
-	Type                 string             `json:"type"`
-	Properties           map[string]*Schema `json:"properties"`
-	Items                *Schema            `json:"items"`
-	Required             *FlexibleRequired  `json:"required"`
-	Ref                  string             `json:"$ref"`
-	AllOf                []Schema           `json:"allOf"`
-	OneOf                []Schema           `json:"oneOf"`
-	AnyOf                []Schema           `json:"anyOf"`
-	Format               string             `json:"format"`
-	Enum                 []any              `json:"enum"`
-	AdditionalProperties any                `json:"additionalProperties"`
+	Type string `json:"type"`
+	// Types holds the alternatives of a 3.1 type list with more than one non-null type, Type is empty then
+	Types []string `json:"-"`
+	// Nullable is set by the 3.0 nullable keyword or a "null" entry in a 3.1 type list
+	Nullable bool `json:"nullable"`
+
+	Title                string                `json:"title,omitempty"`
+	Description          string                `json:"description"`
+	Default              any                   `json:"default,omitempty"`
+	Example              any                   `json:"example,omitempty"`
+	Examples             []any                 `json:"examples,omitempty"`
+	Deprecated           bool                  `json:"deprecated,omitempty"`
+	Properties           map[string]*Schema    `json:"properties"`
+	Items                *Schema               `json:"items"`
+	Required             *FlexibleRequired     `json:"required"`
+	Ref                  string                `json:"$ref"`
+	AllOf                []Schema              `json:"allOf"`
+	OneOf                []Schema              `json:"oneOf"`
+	AnyOf                []Schema              `json:"anyOf"`
+	Format               string                `json:"format"`
+	Enum                 []any                 `json:"enum"`
+	AdditionalProperties *AdditionalProperties `json:"additionalProperties"`
+	Discriminator        *Discriminator        `json:"discriminator,omitempty"`
+	ReadOnly             bool                  `json:"readOnly"`
+	WriteOnly            bool                  `json:"writeOnly"`
+
+	// validation constraints
+	MinLength        *int            `json:"minLength,omitempty"`
+	MaxLength        *int            `json:"maxLength,omitempty"`
+	Pattern          string          `json:"pattern,omitempty"`
+	Minimum          *float64        `json:"minimum,omitempty"`
+	Maximum          *float64        `json:"maximum,omitempty"`
+	ExclusiveMinimum *ExclusiveBound `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *ExclusiveBound `json:"exclusiveMaximum,omitempty"`
+	MultipleOf       *float64        `json:"multipleOf,omitempty"`
+	MinItems         *int            `json:"minItems,omitempty"`
+	MaxItems         *int            `json:"maxItems,omitempty"`
+	UniqueItems      bool            `json:"uniqueItems,omitempty"`
+	MinProperties    *int            `json:"minProperties,omitempty"`
+	MaxProperties    *int            `json:"maxProperties,omitempty"`
+
+	// TypeName names the generated type of an inline schema
+	TypeName string `json:"x-gogen-type-name,omitempty"`
+	// EnumVarNames and EnumDescriptions name and describe the enum values, in the same order
+	EnumVarNames     []string `json:"x-enum-varnames,omitempty"`
+	EnumDescriptions []string `json:"x-enum-descriptions,omitempty"`
+}
+
+// Discriminator tells which schema of a oneOf/anyOf applies based on the value of a property
+type Discriminator struct {
+	PropertyName string            `json:"propertyName"`
+	Mapping      map[string]string `json:"mapping"`
+}
+
+// IsRequired checks if a property of the schema is required, either through the required list
+// or a required flag on the whole object
+func (s *Schema) IsRequired(property string) bool {
+	if s.Required == nil {
+		return false
+	}
+
+	if s.Required.ArrayValue != nil {
+		for _, name := range s.Required.ArrayValue {
+			if name == property {
+				return true
+			}
+		}
+		return false
+	}
+
+	return s.Required.BoolValue != nil && *s.Required.BoolValue
+}
+
+// UnmarshalJSON accepts both a single type and the 3.1 list of types, in which "null" marks the schema as nullable
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	type schemaAlias Schema
+	aux := struct {
+		*schemaAlias
+		Type json.RawMessage `json:"type"`
+	}{schemaAlias: (*schemaAlias)(s)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if len(aux.Type) == 0 {
+		return nil
+	}
+
+	var types []string
+	var single string
+	if err := json.Unmarshal(aux.Type, &single); err == nil {
+		types = []string{single}
+	} else if err := json.Unmarshal(aux.Type, &types); err != nil {
+		return fmt.Errorf("failed to unmarshal type field")
+	}
+
+	s.Type = ""
+	s.Types = nil
+	for _, t := range types {
+		if t == "null" {
+			s.Nullable = true
+			continue
+		}
+		s.Types = append(s.Types, t)
+	}
+
+	switch len(s.Types) {
+	case 0:
+		if s.Nullable {
+			s.Type = "null"
+		}
+	case 1:
+		s.Type = s.Types[0]
+		s.Types = nil
+	}
+
+	return nil
+}
+
+// MarshalJSON writes the type back as a list when the schema allows several types
+func (s Schema) MarshalJSON() ([]byte, error) {
+	type schemaAlias Schema
+	aux := struct {
+		schemaAlias
+		Type any `json:"type,omitempty"`
+	}{schemaAlias: schemaAlias(s)}
+
+	if len(s.Types) > 0 {
+		aux.Type = s.Types
+	} else if s.Type != "" {
+		aux.Type = s.Type
+	}
+
+	return json.Marshal(aux)
 }
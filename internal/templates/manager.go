@@ -36,12 +36,16 @@ func (tm *Manager) LoadTemplates(templatesDir string) error {
 	return nil
 }
 
-// loadLanguageTemplates loads templates for a specific language
+// loadLanguageTemplates loads the embedded templates for a specific language, replaced by the ones a
+// custom templates directory provides; files the directory does not provide keep the embedded template
 func (tm *Manager) loadLanguageTemplates(language, templatesDir string) error {
+	if err := tm.loadEmbeddedTemplates(language); err != nil {
+		return err
+	}
+
 	langDir := filepath.Join(templatesDir, language)
-	if _, err := os.Stat(langDir); os.IsNotExist(err) {
-		// If templates directory doesn't exist, use embedded templates
-		return tm.loadEmbeddedTemplates(language)
+	if _, err := os.Stat(langDir); templatesDir == "" || os.IsNotExist(err) {
+		return nil
 	}
 
 	return filepath.Walk(langDir, func(path string, info os.FileInfo, err error) error {
@@ -58,7 +62,7 @@ func (tm *Manager) loadLanguageTemplates(language, templatesDir string) error {
 			relPath, _ := filepath.Rel(templatesDir, path)
 			templateName := strings.TrimSuffix(relPath, ".tmpl")
 
-			tmpl, err := template.New(templateName).Parse(string(content))
+			tmpl, err := template.New(templateName).Funcs(languageFuncs(language)).Parse(string(content))
 			if err != nil {
 				return err
 			}
@@ -79,6 +83,15 @@ func (tm *Manager) loadEmbeddedTemplates(language string) error {
 	return nil
 }
 
+// languageFuncs returns the functions available to the templates of a language
+func languageFuncs(language string) template.FuncMap {
+	switch language {
+	case "typescript":
+		return typeScriptFuncs
+	}
+	return template.FuncMap{}
+}
+
 // GetTemplate retrieves a template by name
 func (tm *Manager) GetTemplate(name string) (*template.Template, bool) {
 	tmpl, exists := tm.templates[name]
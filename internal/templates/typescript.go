@@ -1,6 +1,8 @@
 package templates
 
 import (
+	"gogen/internal/models"
+	"gogen/internal/utils"
 	"strings"
 	"text/template"
 )
@@ -34,7 +36,7 @@ func (tm *Manager) loadTypeScriptTemplates() error {
   "compilerOptions": {
     "target": "ES2018",
     "module": "commonjs",
-    "lib": ["ES2018"],
+    "lib": ["ES2018", "ES2020.BigInt"],
     "outDir": "./dist",
     "rootDir": "./",
     "strict": true,
@@ -49,26 +51,190 @@ func (tm *Manager) loadTypeScriptTemplates() error {
   "exclude": ["node_modules", "dist"]
 }`,
 
-		"typescript/client": `import axios, { AxiosInstance, AxiosResponse, AxiosRequestConfig } from 'axios';
-import { {{range $i, $t := .Types}}{{if $i}}, {{end}}{{$t.Name}}{{end}} } from './types';
+		"typescript/runtime": typescriptRuntimeTemplate,
+
+		"typescript/validation": typescriptValidationTemplate,
+
+		"typescript/webhooks": typescriptWebhooksTemplate,
+
+		"typescript/client": `{{define "paramSpec"}}{ name: {{Quote .WireName}}, style: '{{.Style}}', explode: {{.Explode}}{{if .AllowReserved}}, allowReserved: true{{end}}{{if .ContentType}}, contentType: {{Quote .ContentType}}{{end}} }{{end}}{{define "paramValue"}}{{if .Shape}}encode({{.Name}}, {{.Shape}}, shapes){{else}}{{.Name}}{{end}}{{end}}{{define "requestContent"}}{ type: {{Quote .ContentType}}{{if .Shape}}, shape: {{.Shape}}{{end}}{{if .Encoding}}, encoding: {{.Encoding}}{{end}} }{{end}}{{define "methodParams"}}{{$sep := ""}}{{range .Parameters}}{{if .Required}}{{$sep}}{{.Name}}: {{.Type}}{{$sep = ", "}}{{end}}{{end}}{{if .RequestBody}}{{$sep}}data{{if not .RequestBody.Required}}?{{end}}: {{.RequestBody.Type}}{{$sep = ", "}}{{end}}{{range .Parameters}}{{if not .Required}}{{$sep}}{{.Name}}?: {{.Type}}{{$sep = ", "}}{{end}}{{end}}{{if .RequestBody}}{{if .RequestBody.ContentTypeParam}}{{$sep}}{{.RequestBody.ContentTypeParam}}?: {{range $i, $c := .RequestBody.Contents}}{{if $i}} | {{end}}{{Quote $c.ContentType}}{{end}}{{end}}{{end}}{{end}}{{define "methodRequest"}}{{if and .RequestBody .RequestBody.Rule}}    if (this.validate{{if not .RequestBody.Required}} && data !== undefined{{end}}) {
+      assertValid(data, {{.RequestBody.Rule}});
+    }
+{{end}}{{if .RequestBody}}    const body = serializeBody(data, {{if .RequestBody.ContentTypeParam}}{
+{{range .RequestBody.Contents}}      {{Quote .ContentType}}: {{template "requestContent" .}},
+{{end}}    }[{{.RequestBody.ContentTypeParam}} || {{Quote .RequestBody.ContentType}}]{{else}}{{template "requestContent" (index .RequestBody.Contents 0)}}{{end}}, shapes);
+{{end}}{{if .PathParams}}    const pathParams = {
+{{range .PathParams}}      {{.Name}}: serializePathParam({{template "paramSpec" .}}, {{template "paramValue" .}}),
+{{end}}    };
+{{end}}{{if .QueryParams}}    const query = serializeQueryParams([
+{{range .QueryParams}}      [{{template "paramSpec" .}}, {{template "paramValue" .}}],
+{{end}}    ]);
+{{end}}    const config: AxiosRequestConfig = {
+      method: '{{.HTTPMethod}}',{{if .Server}}
+      baseURL: resolveServerURL(operationServers[{{Quote .Server.Name}}], this.operationServerVariables[{{Quote .Server.Name}}]),{{end}}
+      url: ` + "`{{.Path}}`" + `{{if .QueryParams}} + (query ? '?' + query : ''){{end}},{{if .RequestBody}}
+      data: body.data,{{end}}{{if or .RequestBody .HeaderParams .CookieParams}}
+      headers: {
+{{if .RequestBody}}        ...body.headers,
+{{end}}{{if .HeaderParams}}        ...serializeHeaderParams([
+{{range .HeaderParams}}          [{{template "paramSpec" .}}, {{template "paramValue" .}}],
+{{end}}        ]),
+{{end}}{{if .CookieParams}}        ...composeCookieHeader([
+{{range .CookieParams}}          [{{template "paramSpec" .}}, {{template "paramValue" .}}],
+{{end}}        ]),
+{{end}}      },{{end}}{{if or (eq .ResponseFormat "text") (eq .ResponseFormat "binary")}}
+      ...responseOptions({{Quote .ResponseFormat}}),{{end}}
+    };{{end}}{{define "methodSend"}}    const response: AxiosResponse<{{.ResponseType}}> = await this.send<{{.ResponseType}}>(config, {{template "methodSecurity" .}}{{if .ErrorShapes}}, {{.ErrorShapes}}{{end}});{{end}}{{define "methodData"}}{{if eq .ResponseFormat "binary"}}toBlob(response.data, response.headers['content-type']){{else if .ResponseShapes}}decode<{{.ResponseType}}>(response.data, statusEntry(response.status, {{.ResponseShapes}}), shapes){{else if .ResponseShape}}decode<{{.ResponseType}}>(response.data, {{.ResponseShape}}, shapes){{else}}response.data{{end}}{{end}}{{define "methodEnvelopeType"}}ApiResponse<{{.ResponseType}}{{if .ResponseHeadersTypeName}}, {{.ResponseHeadersTypeName}}{{end}}>{{end}}{{define "methodEnvelope"}}    return {
+      data: {{template "methodData" .}},
+      status: response.status,
+      headers: parseResponseHeaders(response.headers, {{if .ResponseHeaderSpecs}}{{.ResponseHeaderSpecs}}{{else}}{}{{end}}),
+    };
+{{end}}{{define "methodSecurity"}}[{{range $i, $r := .Security}}{{if $i}}, {{end}}[{{range $j, $name := $r.Schemes}}{{if $j}}, {{end}}{{Quote $name}}{{end}}]{{end}}]{{end}}{{define "serverVariables"}}{{range .}}{{if .Description}}  // {{Comment .Description}}
+{{end}}  {{Quote .Name}}: {{if .Variables}}{
+{{range .Variables}}{{JSDoc "    " .Doc}}    {{.Name}}?: {{.Type}};
+{{end}}  }{{else}}{}{{end}};
+{{end}}{{end}}import axios, { AxiosInstance, AxiosResponse, AxiosRequestConfig } from 'axios';
+import { {{$sep := ""}}{{range .Types}}{{$sep}}{{.Name}}{{$sep = ", "}}{{end}}{{range .BrandedTypes}}{{$sep}}{{.Name}}{{$sep = ", "}}{{end}} } from './types';
+import {
+  serializePathParam,
+  serializeQueryParams,
+  serializeHeaderParams,
+  composeCookieHeader,
+  resolveSecurity,
+  createTokenProviders,
+  isNode,
+  decode,
+  encode,
+  serializeBody,
+  statusEntry,
+  toApiError,
+  responseOptions,
+  toBlob,
+  toFileDownload,{{if or .Servers .OperationServers}}
+  resolveServerURL,{{end}}{{if .Servers}}
+  ServerSpec,{{end}}{{if ne .ResponseMode "data"}}
+  parseResponseHeaders,
+  ApiResponse,{{end}}{{if .LosslessIntegers}}
+  parseJSONResponse,{{end}}
+  BasicCredentials,
+  ApiError,
+  CredentialValue,
+  FileDownload,
+  OAuth2ClientConfig,
+  OAuth2Endpoints,
+  ResolvedSecurity,
+  SecuritySchemeSpec,
+  Shape,
+  TokenProvider,
+} from './runtime';{{if .Validation}}
+import { assertValid } from './validation';{{end}}
+
+// shapes tell which values of the component types are converted between their wire and generated types
+export const shapes: Record<string, Shape> = {
+{{range .Shapes}}  {{Quote .Name}}: {{.Shape}},
+{{end}}};
+
+{{range .Methods}}{{if .ErrorTypeName}}/**
+ * The error responses of {{.Name}}
+ */
+export type {{.ErrorTypeName}} ={{range .Errors}}
+  | ApiError<{{.Status}}, {{.Type}}, {{Quote .StatusCode}}>{{end}}{{if not .DefaultError}}
+  | ApiError<number, unknown, undefined>{{end}};
+
+{{end}}{{end}}{{if ne .ResponseMode "data"}}{{range .Methods}}{{if .ResponseHeadersTypeName}}/**
+ * The headers of the successful responses of {{.Name}}
+ */
+export interface {{.ResponseHeadersTypeName}} {
+{{range .ResponseHeaders}}{{JSDoc "  " .Doc}}  {{.Name}}{{if not .Required}}?{{end}}: {{.Type}};
+{{end}}  [name: string]: unknown;
+}
+
+{{end}}{{end}}{{end}}{{if .Servers}}/**
+ * The variables in the URLs of the servers, by server name
+ */
+export interface {{.ClientClassName}}ServerVariables {
+{{template "serverVariables" .Servers}}}
+
+export type {{.ClientClassName}}ServerName = keyof {{.ClientClassName}}ServerVariables;
+
+/**
+ * Selects a server by name, with values for the variables in its URL; variables not given use their defaults
+ */
+export type {{.ClientClassName}}Server = {
+  [N in {{.ClientClassName}}ServerName]: { name: N; variables?: {{.ClientClassName}}ServerVariables[N] };
+}[{{.ClientClassName}}ServerName];
+
+export const servers: Record<{{.ClientClassName}}ServerName, ServerSpec> = {
+{{range .Servers}}  {{Quote .Name}}: {{.Spec}},
+{{end}}};
+
+{{end}}{{if .OperationServers}}/**
+ * The variables in the URLs of the servers that operations are sent to instead of the servers of the API,
+ * by server name
+ */
+export interface {{.ClientClassName}}OperationServerVariables {
+{{template "serverVariables" .OperationServers}}}
+
+const operationServers: Record<keyof {{.ClientClassName}}OperationServerVariables, ServerSpec> = {
+{{range .OperationServers}}  {{Quote .Name}}: {{.Spec}},
+{{end}}};
+
+{{end}}const securitySchemes: Record<string, SecuritySchemeSpec> = {
+{{range .SecuritySchemes}}  {{.Identifier}}: { type: '{{.Type}}'{{if .Scheme}}, scheme: {{Quote .Scheme}}{{end}}{{if .In}}, in: '{{.In}}'{{end}}{{if .ParamName}}, name: {{Quote .ParamName}}{{end}} },
+{{end}}};
+
+const oauth2Endpoints: Record<string, OAuth2Endpoints> = {
+{{range .SecuritySchemes}}{{if .TokenURL}}  {{.Identifier}}: { tokenUrl: {{Quote .TokenURL}}{{if .RefreshURL}}, refreshUrl: {{Quote .RefreshURL}}{{end}} },
+{{end}}{{end}}};
+
+export interface {{.ClientClassName}}Credentials {
+{{range .SecuritySchemes}}  /**
+   * {{if eq .Type "apiKey"}}API key sent in the {{Quote .ParamName}} {{.In}}{{else if eq .Type "http"}}{{if eq .Scheme "basic"}}HTTP basic credentials{{else if eq .Scheme "bearer"}}HTTP bearer token{{if .BearerFormat}} ({{.BearerFormat}}){{end}}{{else}}HTTP {{.Scheme}} credentials{{end}}{{else if eq .Type "oauth2"}}OAuth2 access token{{else}}OpenID Connect access token{{end}} for the {{Quote .Name}} scheme{{if .Description}}
+   * {{Comment .Description}}{{end}}{{if .TokenURL}}
+   * Pass an OAuth2 client config to fetch tokens from {{.TokenURL}}{{end}}
+   */
+  {{.Identifier}}?: CredentialValue<{{if and (eq .Type "http") (eq .Scheme "basic")}}BasicCredentials{{else}}string{{end}}>{{if or (eq .Type "oauth2") (eq .Type "openIdConnect")}} | TokenProvider{{end}}{{if .TokenURL}} | OAuth2ClientConfig{{end}};
+{{end}}}
 
 export interface {{.ClientClassName}}Config {
-  baseURL: string;
+{{if .Servers}}  // the base URL of the API, taking precedence over the server
+  baseURL?: string;
+  // the server to send requests to, by name or with values for the variables in its URL; defaults to the
+  // first server
+  server?: {{.ClientClassName}}ServerName | {{.ClientClassName}}Server;
+{{else}}  baseURL: string;
+{{end}}{{if .OperationServers}}  // values for the variables in the URLs of the servers of operations, by server name
+  operationServerVariables?: Partial<{{.ClientClassName}}OperationServerVariables>;
+{{end}}
   timeout?: number;
   headers?: Record<string, string>;
+  // send browser cookies with cross-origin requests, required for cookie parameters outside of Node
+  withCredentials?: boolean;
+  // credentials for the security schemes, applied per operation according to its security requirements
+  credentials?: {{.ClientClassName}}Credentials;{{if .Validation}}
+  // check request bodies against the constraints of their schemas, throwing a ValidationError before sending
+  validate?: boolean;{{end}}
 }
 
 export class {{.ClientClassName}} {
   private client: AxiosInstance;
+  private credentials: {{.ClientClassName}}Credentials;{{if .Validation}}
+  private validate: boolean;{{end}}{{if .OperationServers}}
+  private operationServerVariables: Partial<{{.ClientClassName}}OperationServerVariables>;{{end}}
 
   constructor(config: {{.ClientClassName}}Config) {
+    this.credentials = createTokenProviders(config.credentials || {}, oauth2Endpoints);{{if .Validation}}
+    this.validate = config.validate || false;{{end}}{{if .Servers}}
+    const server: { name: {{.ClientClassName}}ServerName; variables?: object } =
+      typeof config.server === 'string' ? { name: config.server } : config.server || { name: {{Quote (index .Servers 0).Name}} };
+{{end}}{{if .OperationServers}}
+    this.operationServerVariables = config.operationServerVariables || {};{{end}}
     this.client = axios.create({
-      baseURL: config.baseURL,
+      baseURL: {{if .Servers}}config.baseURL || resolveServerURL(servers[server.name], server.variables as Record<string, unknown>){{else}}config.baseURL{{end}},
       timeout: config.timeout || 30000,
-      headers: {
-        'Content-Type': 'application/json',
-        ...config.headers,
-      },
+      withCredentials: config.withCredentials,{{if .LosslessIntegers}}
+      transformResponse: [parseJSONResponse],{{end}}
+      headers: config.headers,
     });
   }
 
@@ -80,38 +246,128 @@ export class {{.ClientClassName}} {
     delete this.client.defaults.headers.common['Authorization'];
   }
 
+  public setCredentials(credentials: {{.ClientClassName}}Credentials): void {
+    this.credentials = { ...this.credentials, ...createTokenProviders(credentials, oauth2Endpoints) };
+  }
+
+  private async authorize(config: AxiosRequestConfig, requirements: string[][]): Promise<ResolvedSecurity> {
+    const auth = await resolveSecurity(requirements, securitySchemes, this.credentials as Record<string, unknown>);
+    if (auth.query) {
+      config.url += (config.url!.indexOf('?') === -1 ? '?' : '&') + auth.query;
+    }
+
+    const headers: Record<string, string> = { ...(config.headers as Record<string, string>), ...auth.headers };
+    if (auth.cookie && isNode) {
+      headers['Cookie'] = headers['Cookie'] ? headers['Cookie'] + '; ' + auth.cookie : auth.cookie;
+    }
+    config.headers = headers;
+    return auth;
+  }
+
+  // send sends a request, turning error responses into ApiErrors with their bodies decoded as declared by errors
+  private async send<T>(config: AxiosRequestConfig, requirements: string[][], errors: Record<string, Shape | null> = {}): Promise<AxiosResponse<T>> {
+    try {
+      return await this.sendAuthorized<T>(config, requirements);
+    } catch (error) {
+      throw toApiError(error, errors, shapes);
+    }
+  }
+
+  // sendAuthorized authorizes and sends a request, retrying once with fresh tokens when provider tokens are rejected
+  private async sendAuthorized<T>(config: AxiosRequestConfig, requirements: string[][]): Promise<AxiosResponse<T>> {
+    const request = { ...config };
+    const auth = await this.authorize(request, requirements);
+    try {
+      return await this.client.request<T>(request);
+    } catch (error) {
+      if (auth.tokens.length === 0 || !axios.isAxiosError(error) || error.response?.status !== 401) {
+        throw error;
+      }
+
+      auth.tokens.forEach(([provider, token]) => provider.invalidate(token));
+      const retry = { ...config };
+      await this.authorize(retry, requirements);
+      return this.client.request<T>(retry);
+    }
+  }
+
 {{range .Methods}}
+{{JSDoc "  " .Doc}}  public async {{.Name}}({{template "methodParams" .}}): Promise<{{if eq $.ResponseMode "envelope"}}{{template "methodEnvelopeType" .}}{{else}}{{.ResponseType}}{{end}}> {
+{{template "methodRequest" .}}
+
+{{template "methodSend" .}}
+{{if eq $.ResponseMode "envelope"}}{{template "methodEnvelope" .}}{{else}}    return {{template "methodData" .}};
+{{end}}  }
+{{if eq $.ResponseMode "both"}}
   /**
-   * {{.Summary}}
-   * {{.Description}}
+   * Like {{.Name}}, returning the status and headers of the response along with its data.{{if .Doc.Deprecated}}
+   * @deprecated{{end}}
    */
-  public async {{.Name}}({{range $i, $p := .Parameters}}{{if $i}}, {{end}}{{$p.Name}}{{if not $p.Required}}?{{end}}: {{$p.Type}}{{end}}{{if .RequestBody}}{{if .Parameters}}, {{end}}data{{if not .RequestBody.Required}}?{{end}}: {{.RequestBody.Type}}{{end}}): Promise<{{.ResponseType}}> {
-    const config: AxiosRequestConfig = {
-      method: '{{.HTTPMethod}}',
-      url: ` + "`{{.Path}}`" + `,{{if .RequestBody}}
-      data: data,{{end}}{{if .Parameters}}
-      headers: { {{range $i, $p := .Parameters}}{{if eq $p.In "header"}}{{if $i}} {{end}}{{$p.Name}}, {{end}}{{end}} },
-      params: { {{range $i, $p := .Parameters}}{{if eq $p.In "query"}}{{if $i}}{{end}}{{$p.Name}}, {{end}}{{end}} },{{end}}
-    };
+  public async {{.Name}}WithResponse({{template "methodParams" .}}): Promise<{{template "methodEnvelopeType" .}}> {
+{{template "methodRequest" .}}
+
+{{template "methodSend" .}}
+{{template "methodEnvelope" .}}  }
+{{end}}{{if .DownloadName}}
+  /**
+   * Downloads the response of {{.Name}} as a file, named as suggested by the Content-Disposition header.{{if .Doc.Deprecated}}
+   * @deprecated{{end}}
+   */
+  public async {{.DownloadName}}({{template "methodParams" .}}): Promise<FileDownload> {
+{{template "methodRequest" .}}
 
-    const response: AxiosResponse<{{.ResponseType}}> = await this.client.request(config);
-    return response.data;
+    const response = await this.send<Blob | ArrayBuffer>(config, {{template "methodSecurity" .}}{{if .ErrorShapes}}, {{.ErrorShapes}}{{end}});
+    return toFileDownload(response);
   }
-{{end}}
+{{end}}{{end}}
 }`,
 
 		"typescript/types": `// Generated types from OpenAPI specification
-{{range .Types}}
-{{if eq .Type "interface"}}export interface {{.Name}} {
-{{range .Properties}}
-  {{.Name}}{{if not .Required}}?{{end}}: {{.Type}};
-{{end}}
+{{range .BrandedTypes}}
+export type {{.Name}} = {{.Type}} & { readonly __brand: {{Quote .Name}} };
+{{end}}{{range .Types}}
+{{JSDoc "" .Doc}}{{if eq .Kind "object"}}export interface {{.Name}}{{range $i, $base := .Extends}}{{if $i}},{{else}} extends{{end}} {{$base}}{{end}} {
+{{range .Properties}}{{JSDoc "  " .Doc}}  {{if .ReadOnly}}readonly {{end}}{{.Name}}{{if not .Required}}?{{end}}: {{.Type}};
+{{end}}{{if .IndexSignatureType}}  [key: string]: {{.IndexSignatureType}};
+{{end}}}
+{{else if and (eq .Kind "union") .Discriminator}}{{$union := .}}export type {{.Name}} ={{range .Discriminator.Variants}}
+  | {{if .Tagged}}({{.TypeName}} & { {{Quote $union.Discriminator.PropertyName}}: {{.ValueType}} }){{else}}{{.TypeName}}{{end}}{{end}};
+{{range .Discriminator.Variants}}
+export function {{.GuardName}}(value: {{$union.Name}}): value is {{if .Tagged}}{{.TypeName}} & { {{Quote $union.Discriminator.PropertyName}}: {{.ValueType}} }{{else}}{{.TypeName}}{{end}} {
+  return {{range $i, $v := .Values}}{{if $i}} || {{end}}value[{{Quote $union.Discriminator.PropertyName}}] === {{Quote $v}}{{end}};
 }
-{{else}}export type {{.Name}} = {{.Type}};{{end}}
-{{end}}`,
+{{end}}{{else if and (eq .Kind "enum") (eq $.EnumStyle "enum")}}export enum {{.Name}} {
+{{range .EnumValues}}{{if .Description}}  /** {{Comment .Description}} */
+{{end}}  {{.Name}} = {{.Value}},
+{{end}}}
+{{else if and (eq .Kind "enum") (eq $.EnumStyle "const")}}export const {{.Name}} = {
+{{range .EnumValues}}{{if .Description}}  /** {{Comment .Description}} */
+{{end}}  {{.Name}}: {{.Value}},
+{{end}}} as const;
 
-		"typescript/index": `export { {{.ClientClassName}} } from './client';
-export * from './types';`,
+export type {{.Name}} = (typeof {{.Name}})[keyof typeof {{.Name}}]{{if .Nullable}} | null{{end}};
+{{else}}export type {{.Name}} = {{.Type}};
+{{end}}{{end}}`,
+
+		"typescript/index": `export { {{.ClientClassName}}{{if .Servers}}, servers{{end}} } from './client';
+export type { {{.ClientClassName}}Config, {{.ClientClassName}}Credentials{{if .Servers}}, {{.ClientClassName}}ServerVariables, {{.ClientClassName}}ServerName, {{.ClientClassName}}Server{{end}}{{if .OperationServers}}, {{.ClientClassName}}OperationServerVariables{{end}}{{range .Methods}}{{if .ErrorTypeName}}, {{.ErrorTypeName}}{{end}}{{if and .ResponseHeadersTypeName (ne $.ResponseMode "data")}}, {{.ResponseHeadersTypeName}}{{end}}{{end}} } from './client';
+export { ApiError, OAuth2TokenProvider, saveDownload, resolveServerURL } from './runtime';
+export type { TokenProvider, OAuth2TokenProviderOptions, OAuth2ClientConfig, BasicCredentials, FileDownload, ApiResponse, ServerSpec } from './runtime';
+export * from './types';{{if .Validation}}
+export { ValidationError, validate, assertValid, rules } from './validation';
+export type { Rule, ValidationIssue } from './validation';{{end}}{{if .Webhooks}}
+export { handleWebhook, parseWebhookPayload, createHmacVerifier, WebhookSignatureError } from './webhooks';
+export type {
+  WebhookHandler,
+  WebhookRequest,
+  WebhookHeaders,
+  SignatureVerifier,
+  HmacVerifierOptions,
+  {{.ClientClassName}}Webhooks,
+  {{.ClientClassName}}WebhookHandlers,{{range .Webhooks}}
+  {{.TypeName}}Payload,
+  {{.TypeName}}Handler,{{end}}
+} from './webhooks';{{end}}`,
 
 		"typescript/README.md": `# {{.ProjectName}} Client
 
@@ -129,25 +385,35 @@ npm install {{.ProjectName | ToLower}}-client
 import { {{.ClientClassName}} } from '{{.ProjectName | ToLower}}-client';
 
 const client = new {{.ClientClassName}}({
-  baseURL: '{{.BaseURL}}',
-  timeout: 30000,
+{{if .Servers}}  server: {{Quote (index .Servers 0).Name}},{{else}}  baseURL: '{{.BaseURL}}',{{end}}
+  timeout: 30000,{{if .SecuritySchemes}}
+  credentials: {
+{{range .SecuritySchemes}}    {{.Identifier}}: {{if and (eq .Type "http") (eq .Scheme "basic")}}{ username: 'user', password: 'secret' }{{else if .TokenURL}}{ clientId: 'your-client-id', clientSecret: 'your-client-secret' }{{else}}'your-{{if eq .Type "apiKey"}}api-key{{else}}token{{end}}'{{end}},
+{{end}}  },{{end}}
 });
-
-// Set authentication token if needed
-client.setAuthToken('your-jwt-token');
 ` + "```" + `
 
+{{if .SecuritySchemes}}Credentials are applied per operation according to its security requirements, and can also be
+functions returning the value (or a promise of it) so tokens are fetched when needed.
+{{range .SecuritySchemes}}{{if .TokenURL}}
+The ` + "`{{.Identifier}}`" + ` OAuth2 credentials can be a client config, turned into an ` + "`OAuth2TokenProvider`" + ` that fetches
+tokens from {{.TokenURL}} with the client credentials grant (or the refresh token grant when a
+` + "`refreshToken`" + ` is given), caches them until they expire and refreshes them when the API answers 401.
+{{end}}{{end}}{{else}}The API declares no security schemes; use ` + "`client.setAuthToken('your-jwt-token')`" + ` to send a bearer token anyway.{{end}}
+
+### Cookie parameters
+
+In Node, cookie parameters are sent in a ` + "`Cookie`" + ` header composed by the client. Browsers do not allow
+scripts to set that header, so there the cookies come from the browser cookie jar; pass
+` + "`withCredentials: true`" + ` in the client config when calling a different origin.
+
 ## License
 
 MIT`,
 	}
 
-	funcMap := template.FuncMap{
-		"ToLower": strings.ToLower,
-	}
-
 	for name, content := range templates {
-		tmpl, err := template.New(name).Funcs(funcMap).Parse(content)
+		tmpl, err := template.New(name).Funcs(typeScriptFuncs).Parse(content)
 		if err != nil {
 			return err
 		}
@@ -156,3 +422,59 @@ MIT`,
 
 	return nil
 }
+
+// typeScriptFuncs are the functions available to the TypeScript templates, embedded or custom
+var typeScriptFuncs = template.FuncMap{
+	"ToLower": strings.ToLower,
+	"Quote":   utils.QuoteString,
+	"Comment": utils.InlineComment,
+	"JSDoc":   jsDoc,
+}
+
+// jsDoc renders documentation as a JSDoc comment with every line indented, or nothing when there is none
+func jsDoc(indent string, doc models.DocModel) string {
+	var lines []string
+	addText := func(text string) {
+		for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+			lines = append(lines, strings.TrimRight(line, " \t\r"))
+		}
+	}
+
+	if doc.Title != "" {
+		addText(doc.Title)
+	}
+	if doc.Description != "" {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		addText(doc.Description)
+	}
+	if doc.Default != "" {
+		lines = append(lines, "@default "+doc.Default)
+	}
+	if doc.Example != "" {
+		lines = append(lines, "@example "+doc.Example)
+	}
+	if doc.Deprecated {
+		lines = append(lines, "@deprecated")
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+	if len(lines) == 1 {
+		return indent + "/** " + strings.ReplaceAll(lines[0], "*/", "*\\/") + " */\n"
+	}
+
+	var b strings.Builder
+	b.WriteString(indent + "/**\n")
+	for _, line := range lines {
+		if line == "" {
+			b.WriteString(indent + " *\n")
+		} else {
+			b.WriteString(indent + " * " + strings.ReplaceAll(line, "*/", "*\\/") + "\n")
+		}
+	}
+	b.WriteString(indent + " */\n")
+	return b.String()
+}
@@ -0,0 +1,995 @@
+package templates
+
+// typescriptRuntimeTemplate holds the helpers shared by the generated TypeScript client
+const typescriptRuntimeTemplate = `// Generated runtime helpers for the {{.ProjectName}} client
+import axios, { AxiosRequestConfig, AxiosResponse } from 'axios';
+
+export type ParamStyle =
+  | 'form'
+  | 'spaceDelimited'
+  | 'pipeDelimited'
+  | 'deepObject'
+  | 'simple'
+  | 'label'
+  | 'matrix';
+
+export interface ParamSpec {
+  name: string;
+  style: ParamStyle;
+  explode: boolean;
+  allowReserved?: boolean;
+  contentType?: string;
+}
+
+type ParamEntry = [ParamSpec, unknown];
+
+function isObject(value: unknown): value is Record<string, unknown> {
+  return typeof value === 'object' && value !== null && !Array.isArray(value) && !(value instanceof Date);
+}
+
+function definedEntries(value: Record<string, unknown>): Array<[string, unknown]> {
+  return Object.keys(value)
+    .filter((key) => value[key] !== undefined)
+    .map((key): [string, unknown] => [key, value[key]]);
+}
+
+function toPrimitiveString(value: unknown): string {
+  if (value === null) {
+    return '';
+  }
+  if (value instanceof Date) {
+    return value.toISOString();
+  }
+  return String(value);
+}
+
+function serializeContent(contentType: string, value: unknown): string {
+  if (contentType.indexOf('json') !== -1) {
+    return stringifyJSON(value);
+  }
+  return toPrimitiveString(value);
+}
+
+// encodeQueryValue percent-encodes a query value, leaving RFC3986 reserved characters intact when allowed
+function encodeQueryValue(value: string, allowReserved?: boolean): string {
+  if (!allowReserved) {
+    return encodeURIComponent(value);
+  }
+  return value.replace(/[^A-Za-z0-9\-._~:/?#\[\]@!$&'()*+,;=]+/g, (chars) => encodeURIComponent(chars));
+}
+
+/**
+ * Serializes a path parameter using the simple, label or matrix style.
+ */
+export function serializePathParam(spec: ParamSpec, value: unknown): string {
+  const name = encodeURIComponent(spec.name);
+  if (spec.contentType) {
+    return encodeURIComponent(serializeContent(spec.contentType, value));
+  }
+
+  const encode = (item: unknown) => encodeURIComponent(toPrimitiveString(item));
+  const items: string[] = [];
+  let pairs = false;
+
+  if (Array.isArray(value)) {
+    value.forEach((item) => items.push(encode(item)));
+  } else if (isObject(value)) {
+    pairs = spec.explode;
+    definedEntries(value).forEach(([key, item]) => {
+      if (spec.explode) {
+        items.push(encodeURIComponent(key) + '=' + encode(item));
+      } else {
+        items.push(encodeURIComponent(key), encode(item));
+      }
+    });
+  } else {
+    items.push(encode(value));
+  }
+
+  switch (spec.style) {
+    case 'label':
+      return '.' + items.join(spec.explode ? '.' : ',');
+    case 'matrix':
+      if (pairs) {
+        return items.map((item) => ';' + item).join('');
+      }
+      if (spec.explode && Array.isArray(value)) {
+        return items.map((item) => ';' + name + '=' + item).join('');
+      }
+      return ';' + name + '=' + items.join(',');
+    default:
+      return items.join(',');
+  }
+}
+
+function serializeDeepObject(prefix: string, value: unknown, encode: (item: unknown) => string, parts: string[]): void {
+  if (isObject(value)) {
+    definedEntries(value).forEach(([key, item]) => {
+      serializeDeepObject(prefix + '[' + encodeURIComponent(key) + ']', item, encode, parts);
+    });
+  } else if (Array.isArray(value)) {
+    value.forEach((item) => serializeDeepObject(prefix + '[]', item, encode, parts));
+  } else {
+    parts.push(prefix + '=' + encode(value));
+  }
+}
+
+function serializeQueryParam(spec: ParamSpec, value: unknown, parts: string[]): void {
+  const name = encodeURIComponent(spec.name);
+  const encode = (item: unknown) => encodeQueryValue(toPrimitiveString(item), spec.allowReserved);
+
+  if (spec.contentType) {
+    parts.push(name + '=' + encodeQueryValue(serializeContent(spec.contentType, value), spec.allowReserved));
+    return;
+  }
+
+  const delimiter = spec.style === 'spaceDelimited' ? '%20' : spec.style === 'pipeDelimited' ? '|' : ',';
+
+  if (Array.isArray(value)) {
+    if (spec.explode) {
+      value.forEach((item) => parts.push(name + '=' + encode(item)));
+    } else {
+      parts.push(name + '=' + value.map(encode).join(delimiter));
+    }
+    return;
+  }
+
+  if (isObject(value)) {
+    if (spec.style === 'deepObject') {
+      serializeDeepObject(name, value, encode, parts);
+    } else if (spec.explode) {
+      definedEntries(value).forEach(([key, item]) => parts.push(encodeURIComponent(key) + '=' + encode(item)));
+    } else {
+      const items: string[] = [];
+      definedEntries(value).forEach(([key, item]) => items.push(encodeURIComponent(key), encode(item)));
+      parts.push(name + '=' + items.join(delimiter));
+    }
+    return;
+  }
+
+  parts.push(name + '=' + encode(value));
+}
+
+/**
+ * Serializes query parameters using the form, spaceDelimited, pipeDelimited or deepObject style.
+ * Undefined values are skipped.
+ */
+export function serializeQueryParams(params: ParamEntry[]): string {
+  const parts: string[] = [];
+  params.forEach(([spec, value]) => {
+    if (value !== undefined) {
+      serializeQueryParam(spec, value, parts);
+    }
+  });
+  return parts.join('&');
+}
+
+function serializeSimpleValue(spec: ParamSpec, value: unknown): string {
+  if (spec.contentType) {
+    return serializeContent(spec.contentType, value);
+  }
+  if (Array.isArray(value)) {
+    return value.map(toPrimitiveString).join(',');
+  }
+  if (isObject(value)) {
+    const items: string[] = [];
+    definedEntries(value).forEach(([key, item]) => {
+      if (spec.explode) {
+        items.push(key + '=' + toPrimitiveString(item));
+      } else {
+        items.push(key, toPrimitiveString(item));
+      }
+    });
+    return items.join(',');
+  }
+  return toPrimitiveString(value);
+}
+
+/**
+ * Serializes header parameters using the simple style. Undefined values are skipped.
+ */
+export function serializeHeaderParams(params: ParamEntry[]): Record<string, string> {
+  const headers: Record<string, string> = {};
+  params.forEach(([spec, value]) => {
+    if (value !== undefined) {
+      headers[spec.name] = serializeSimpleValue(spec, value);
+    }
+  });
+  return headers;
+}
+
+export const isNode =
+  typeof process !== 'undefined' && process.versions != null && process.versions.node != null;
+
+/**
+ * Serializes cookie parameters using the form style into a Cookie header value. Undefined values are skipped.
+ */
+export function serializeCookieParams(params: ParamEntry[]): string {
+  const pairs: string[] = [];
+  params.forEach(([spec, value]) => {
+    if (value === undefined) {
+      return;
+    }
+
+    const name = encodeURIComponent(spec.name);
+    const encode = (item: unknown) => encodeURIComponent(toPrimitiveString(item));
+    if (spec.contentType) {
+      pairs.push(name + '=' + encodeURIComponent(serializeContent(spec.contentType, value)));
+    } else if (Array.isArray(value)) {
+      if (spec.explode) {
+        value.forEach((item) => pairs.push(name + '=' + encode(item)));
+      } else {
+        pairs.push(name + '=' + value.map(encode).join(','));
+      }
+    } else if (isObject(value)) {
+      const entries = definedEntries(value);
+      if (spec.explode) {
+        entries.forEach(([key, item]) => pairs.push(encodeURIComponent(key) + '=' + encode(item)));
+      } else {
+        const items: string[] = [];
+        entries.forEach(([key, item]) => items.push(encodeURIComponent(key), encode(item)));
+        pairs.push(name + '=' + items.join(','));
+      }
+    } else {
+      pairs.push(name + '=' + encode(value));
+    }
+  });
+  return pairs.join('; ');
+}
+
+/**
+ * Composes the Cookie header for cookie parameters.
+ *
+ * Browsers forbid setting the Cookie header from scripts, so outside of Node this returns no headers and
+ * cookies are sent from the browser cookie jar instead (enable withCredentials for cross-origin requests).
+ */
+export function composeCookieHeader(params: ParamEntry[]): Record<string, string> {
+  const cookie = serializeCookieParams(params);
+  if (!isNode || cookie === '') {
+    return {};
+  }
+  return { Cookie: cookie };
+}
+
+export type CredentialValue<T> = T | (() => T | Promise<T>);
+
+export interface BasicCredentials {
+  username: string;
+  password: string;
+}
+
+export interface SecuritySchemeSpec {
+  type: 'apiKey' | 'http' | 'oauth2' | 'openIdConnect';
+  scheme?: string;
+  in?: 'header' | 'query' | 'cookie';
+  name?: string;
+}
+
+export interface ResolvedSecurity {
+  headers: Record<string, string>;
+  query: string;
+  cookie: string;
+  // tokens obtained from token providers, invalidated and refreshed when the server rejects them
+  tokens: Array<[TokenProvider, string]>;
+}
+
+/**
+ * Supplies access tokens, caching them and fetching new ones when they expire or are rejected.
+ */
+export interface TokenProvider {
+  getToken(): Promise<string>;
+  // invalidate drops the cached token, if it is still the given one
+  invalidate(token?: string): void;
+}
+
+export function isTokenProvider(value: unknown): value is TokenProvider {
+  return typeof value === 'object' && value !== null && typeof (value as TokenProvider).getToken === 'function';
+}
+
+export interface OAuth2TokenProviderOptions {
+  tokenUrl: string;
+  refreshUrl?: string;
+  clientId: string;
+  clientSecret?: string;
+  scopes?: string[];
+  // refresh token from an earlier grant; when present tokens are obtained with the refresh_token grant
+  refreshToken?: string;
+  // how the client credentials are sent to the token endpoint, defaults to HTTP basic authentication
+  clientAuthentication?: 'basic' | 'body';
+  // seconds before the reported expiry at which a token is considered expired, defaults to 30
+  expirySkew?: number;
+}
+
+interface OAuth2TokenResponse {
+  access_token: string;
+  expires_in?: number;
+  refresh_token?: string;
+}
+
+/**
+ * Fetches OAuth2 access tokens with the client credentials grant, or the refresh token grant when a refresh
+ * token is available. Tokens are cached until they expire, and concurrent requests for a new token share a
+ * single token request.
+ */
+export class OAuth2TokenProvider implements TokenProvider {
+  private accessToken?: string;
+  private expiresAt = 0;
+  private refreshToken?: string;
+  private pending?: Promise<string>;
+
+  constructor(private options: OAuth2TokenProviderOptions) {
+    this.refreshToken = options.refreshToken;
+  }
+
+  public async getToken(): Promise<string> {
+    if (this.accessToken !== undefined && Date.now() < this.expiresAt) {
+      return this.accessToken;
+    }
+
+    if (!this.pending) {
+      this.pending = this.fetchToken().finally(() => {
+        this.pending = undefined;
+      });
+    }
+    return this.pending;
+  }
+
+  public invalidate(token?: string): void {
+    if (token === undefined || token === this.accessToken) {
+      this.accessToken = undefined;
+      this.expiresAt = 0;
+    }
+  }
+
+  private async fetchToken(): Promise<string> {
+    let response: OAuth2TokenResponse;
+    if (this.refreshToken !== undefined) {
+      try {
+        response = await this.requestToken(this.options.refreshUrl || this.options.tokenUrl, {
+          grant_type: 'refresh_token',
+          refresh_token: this.refreshToken,
+        });
+      } catch (error) {
+        // an expired or revoked refresh token can only be replaced when the client credentials grant is usable
+        if (this.options.clientSecret === undefined) {
+          throw error;
+        }
+        this.refreshToken = undefined;
+        return this.fetchToken();
+      }
+    } else {
+      const params: Record<string, string> = { grant_type: 'client_credentials' };
+      if (this.options.scopes && this.options.scopes.length > 0) {
+        params.scope = this.options.scopes.join(' ');
+      }
+      response = await this.requestToken(this.options.tokenUrl, params);
+    }
+
+    const skew = this.options.expirySkew !== undefined ? this.options.expirySkew : 30;
+    this.accessToken = response.access_token;
+    this.expiresAt =
+      response.expires_in !== undefined ? Date.now() + (response.expires_in - skew) * 1000 : Number.POSITIVE_INFINITY;
+    if (response.refresh_token !== undefined) {
+      this.refreshToken = response.refresh_token;
+    }
+    return response.access_token;
+  }
+
+  private async requestToken(url: string, params: Record<string, string>): Promise<OAuth2TokenResponse> {
+    const body = new URLSearchParams(params);
+    const headers: Record<string, string> = { 'Content-Type': 'application/x-www-form-urlencoded' };
+
+    if (this.options.clientAuthentication === 'body' || this.options.clientSecret === undefined) {
+      body.set('client_id', this.options.clientId);
+      if (this.options.clientSecret !== undefined) {
+        body.set('client_secret', this.options.clientSecret);
+      }
+    } else {
+      headers['Authorization'] =
+        'Basic ' +
+        toBase64(encodeURIComponent(this.options.clientId) + ':' + encodeURIComponent(this.options.clientSecret));
+    }
+
+    const response = await axios.post<OAuth2TokenResponse>(url, body.toString(), { headers });
+    return response.data;
+  }
+}
+
+export type OAuth2ClientConfig = Omit<OAuth2TokenProviderOptions, 'tokenUrl'> & { tokenUrl?: string };
+
+export interface OAuth2Endpoints {
+  tokenUrl: string;
+  refreshUrl?: string;
+}
+
+/**
+ * Replaces OAuth2 client configs in the credentials with token providers, using the token endpoints
+ * declared by the security schemes unless overridden.
+ */
+export function createTokenProviders<T extends object>(credentials: T, endpoints: Record<string, OAuth2Endpoints>): T {
+  const resolved: Record<string, unknown> = { ...credentials };
+  Object.keys(endpoints).forEach((name) => {
+    const value = resolved[name];
+    if (typeof value === 'object' && value !== null && !isTokenProvider(value) && 'clientId' in value) {
+      resolved[name] = new OAuth2TokenProvider({ ...endpoints[name], ...(value as OAuth2ClientConfig) } as OAuth2TokenProviderOptions);
+    }
+  });
+  return resolved as T;
+}
+
+async function resolveCredential<T>(value: CredentialValue<T>): Promise<T> {
+  if (typeof value === 'function') {
+    return (value as () => T | Promise<T>)();
+  }
+  return value;
+}
+
+function toBase64(value: string): string {
+  if (isNode) {
+    return Buffer.from(value, 'utf8').toString('base64');
+  }
+  return btoa(unescape(encodeURIComponent(value)));
+}
+
+/**
+ * Resolves the credentials for the first security requirement that can be satisfied with the configured
+ * credentials. Requirements are alternatives; the schemes within a requirement are all applied.
+ * Operations without requirements, or with only anonymous ones, resolve to nothing.
+ */
+export async function resolveSecurity(
+  requirements: string[][],
+  schemes: Record<string, SecuritySchemeSpec>,
+  credentials: Record<string, unknown> = {},
+): Promise<ResolvedSecurity> {
+  const resolved: ResolvedSecurity = { headers: {}, query: '', cookie: '', tokens: [] };
+  const requirement = requirements.find(
+    (names) => names.length > 0 && names.every((name) => credentials[name] !== undefined),
+  );
+  if (!requirement) {
+    return resolved;
+  }
+
+  const query: string[] = [];
+  const cookies: string[] = [];
+  for (const name of requirement) {
+    const scheme = schemes[name];
+    const value = credentials[name];
+    let credential: unknown;
+    if (isTokenProvider(value)) {
+      credential = await value.getToken();
+      resolved.tokens.push([value, credential as string]);
+    } else {
+      credential = await resolveCredential(value as CredentialValue<unknown>);
+    }
+
+    if (scheme.type === 'apiKey') {
+      const key = String(credential);
+      if (scheme.in === 'query') {
+        query.push(encodeURIComponent(scheme.name!) + '=' + encodeURIComponent(key));
+      } else if (scheme.in === 'cookie') {
+        cookies.push(encodeURIComponent(scheme.name!) + '=' + encodeURIComponent(key));
+      } else {
+        resolved.headers[scheme.name!] = key;
+      }
+    } else if (scheme.type === 'http' && scheme.scheme === 'basic') {
+      const basic = credential as BasicCredentials;
+      resolved.headers['Authorization'] = 'Basic ' + toBase64(basic.username + ':' + basic.password);
+    } else if (scheme.type === 'http' && scheme.scheme !== 'bearer') {
+      resolved.headers['Authorization'] = scheme.scheme + ' ' + String(credential);
+    } else {
+      resolved.headers['Authorization'] = 'Bearer ' + String(credential);
+    }
+  }
+
+  resolved.query = query.join('&');
+  resolved.cookie = cookies.join('; ');
+  return resolved;
+}
+
+/**
+ * Describes where a value needs converting between its wire and generated representation: a codec name,
+ * a reference to a named shape, or the shapes of array items, object properties, extra object properties,
+ * allOf parts or discriminated variants.
+ */
+export type Shape =
+  | string
+  | { ref: string }
+  | { items: Shape }
+  | { properties?: Record<string, Shape>; values?: Shape }
+  | { all: Shape[] }
+  | { discriminator: string; mapping: Record<string, Shape> };
+
+interface Codec {
+  decode(value: unknown): unknown;
+  encode(value: unknown): unknown;
+}
+
+function isIntegerString(value: unknown): value is string {
+  return typeof value === 'string' && /^-?\d+$/.test(value);
+}
+
+const codecs: Record<string, Codec> = {
+  date: {
+    decode: (value) => (typeof value === 'string' ? new Date(value) : value),
+    encode: (value) => (value instanceof Date ? value.toISOString().slice(0, 10) : value),
+  },
+  'date-time': {
+    decode: (value) => (typeof value === 'string' ? new Date(value) : value),
+    encode: (value) => (value instanceof Date ? value.toISOString() : value),
+  },
+  bigint: {
+    decode: (value) => (typeof value === 'number' || isIntegerString(value) ? BigInt(value) : value),
+    encode: (value) => value,
+  },
+  'integer-string': {
+    decode: (value) => (typeof value === 'number' ? String(value) : value),
+    encode: (value) => (isIntegerString(value) ? BigInt(value) : value),
+  },
+};
+
+function convert(value: unknown, shape: Shape | undefined, shapes: Record<string, Shape>, direction: keyof Codec): unknown {
+  if (value === null || value === undefined || shape === undefined) {
+    return value;
+  }
+  if (typeof shape === 'string') {
+    return codecs[shape] ? codecs[shape][direction](value) : value;
+  }
+  if ('ref' in shape) {
+    return convert(value, shapes[shape.ref], shapes, direction);
+  }
+  if ('items' in shape) {
+    return Array.isArray(value) ? value.map((item) => convert(item, shape.items, shapes, direction)) : value;
+  }
+  if ('all' in shape) {
+    return shape.all.reduce((result, part) => convert(result, part, shapes, direction), value);
+  }
+  if (!isObject(value)) {
+    return value;
+  }
+  if ('discriminator' in shape) {
+    return convert(value, shape.mapping[String(value[shape.discriminator])], shapes, direction);
+  }
+
+  const result: Record<string, unknown> = {};
+  Object.keys(value).forEach((key) => {
+    const fixed = shape.properties && Object.prototype.hasOwnProperty.call(shape.properties, key);
+    result[key] = convert(value[key], fixed ? shape.properties![key] : shape.values, shapes, direction);
+  });
+  return result;
+}
+
+/**
+ * Converts a value received on the wire to its generated representation, e.g. date-time strings to Date objects.
+ */
+export function decode<T>(value: unknown, shape: Shape | undefined, shapes: Record<string, Shape>): T {
+  return convert(value, shape, shapes, 'decode') as T;
+}
+
+/**
+ * Converts a value to its wire representation, e.g. Date objects to date-time strings.
+ */
+export function encode(value: unknown, shape: Shape, shapes: Record<string, Shape>): unknown {
+  return convert(value, shape, shapes, 'encode');
+}
+
+/**
+ * Converts a value to its wire representation and serializes it as JSON.
+ */
+export function encodeJSON(value: unknown, shape: Shape, shapes: Record<string, Shape>): string | undefined {
+  return value === undefined ? undefined : stringifyJSON(encode(value, shape, shapes));
+}
+
+/**
+ * Describes how a property of a multipart or form request body is serialized.
+ */
+export interface PartEncoding {
+  contentType?: string;
+  style?: ParamStyle;
+  explode?: boolean;
+  allowReserved?: boolean;
+}
+
+/**
+ * Describes a content type a request body can be sent as, with the codec shape of the body and the encoding
+ * of its properties.
+ */
+export interface RequestContent {
+  type: string;
+  shape?: Shape;
+  encoding?: Record<string, PartEncoding>;
+}
+
+export interface SerializedBody {
+  data: unknown;
+  headers: Record<string, string>;
+}
+
+function isBinary(value: unknown): value is Blob | ArrayBuffer | ArrayBufferView {
+  return (typeof Blob !== 'undefined' && value instanceof Blob) || value instanceof ArrayBuffer || ArrayBuffer.isView(value);
+}
+
+// appendPart appends a property to a multipart body: files as they are, arrays as one part per item,
+// objects as JSON and anything else as text, unless the encoding of the property names a content type
+function appendPart(form: FormData, name: string, value: unknown, encoding: PartEncoding | undefined): void {
+  if (value === undefined) {
+    return;
+  }
+  if (typeof Blob !== 'undefined' && value instanceof Blob) {
+    form.append(name, value);
+    return;
+  }
+  if (isBinary(value)) {
+    form.append(name, new Blob([value], { type: (encoding && encoding.contentType) || 'application/octet-stream' }));
+    return;
+  }
+
+  const contentType = encoding && encoding.contentType;
+  if (Array.isArray(value) && !(contentType && contentType.indexOf('json') !== -1)) {
+    value.forEach((item) => appendPart(form, name, item, encoding));
+    return;
+  }
+
+  if (contentType && contentType.indexOf('text/plain') === -1) {
+    form.append(name, new Blob([serializeContent(contentType, value)], { type: contentType }));
+  } else if (isObject(value) || Array.isArray(value)) {
+    form.append(name, new Blob([stringifyJSON(value)], { type: 'application/json' }));
+  } else {
+    form.append(name, toPrimitiveString(value));
+  }
+}
+
+/**
+ * Serializes a request body for its content type: JSON, multipart/form-data with file parts, url-encoded
+ * forms, text, or binary data sent as it is. Returns the body with the Content-Type header to send.
+ */
+export function serializeBody(value: unknown, content: RequestContent, shapes: Record<string, Shape>): SerializedBody {
+  if (value === undefined) {
+    return { data: undefined, headers: {} };
+  }
+
+  const data = content.shape ? encode(value, content.shape, shapes) : value;
+  const type = content.type.split(';')[0].trim().toLowerCase();
+  const encoding = content.encoding || {};
+
+  if (type === 'multipart/form-data' && isObject(data)) {
+    // the boundary is added to the Content-Type header when the form is sent
+    const form = new FormData();
+    definedEntries(data).forEach(([name, item]) => appendPart(form, name, item, encoding[name]));
+    return { data: form, headers: {} };
+  }
+
+  if (type === 'application/x-www-form-urlencoded' && isObject(data)) {
+    const fields = definedEntries(data).map(([name, item]): ParamEntry => {
+      const part = encoding[name] || {};
+      const style = part.style || 'form';
+      const spec: ParamSpec = {
+        name,
+        style,
+        explode: part.explode !== undefined ? part.explode : style === 'form',
+        allowReserved: part.allowReserved,
+        contentType: part.contentType,
+      };
+      return [spec, item];
+    });
+    return { data: serializeQueryParams(fields), headers: { 'Content-Type': content.type } };
+  }
+
+  const headers: Record<string, string> = content.type.indexOf('*') === -1 ? { 'Content-Type': content.type } : {};
+  if (type.indexOf('json') !== -1) {
+    return { data: stringifyJSON(data), headers };
+  }
+  if (type.indexOf('text/') === 0) {
+    return { data: toPrimitiveString(data), headers };
+  }
+  return { data, headers };
+}
+
+/**
+ * Serializes a value as JSON, writing bigint values as plain numbers.
+ */
+export function stringifyJSON(value: unknown): string {
+  const marker = '\u0000bigint:';
+  const text = JSON.stringify(value, (_key, item) => (typeof item === 'bigint' ? marker + item.toString() : item));
+  return text.replace(/"\\u0000bigint:(-?\d+)"/g, '$1');
+}
+
+/**
+ * Parses JSON, reading integers beyond the safe integer range as strings so no digits are lost.
+ */
+export function parseJSON(text: string): unknown {
+  let result = '';
+  let start = 0;
+  let inString = false;
+
+  for (let i = 0; i < text.length; i++) {
+    const char = text[i];
+    if (inString) {
+      if (char === '\\') {
+        i++;
+      } else if (char === '"') {
+        inString = false;
+      }
+      continue;
+    }
+
+    if (char === '"') {
+      inString = true;
+    } else if (char === '-' || (char >= '0' && char <= '9')) {
+      let end = i + 1;
+      while (end < text.length && /[0-9.eE+\-]/.test(text[end])) {
+        end++;
+      }
+
+      const number = text.slice(i, end);
+      if (isIntegerString(number) && !Number.isSafeInteger(Number(number))) {
+        result += text.slice(start, i) + '"' + number + '"';
+        start = end;
+      }
+      i = end - 1;
+    }
+  }
+
+  return JSON.parse(result + text.slice(start));
+}
+
+/**
+ * Parses JSON response bodies with parseJSON, leaving other bodies as they are.
+ */
+export function parseJSONResponse(data: unknown): unknown {
+  if (typeof data !== 'string' || data.trim() === '') {
+    return data;
+  }
+  try {
+    return parseJSON(data);
+  } catch {
+    return data;
+  }
+}
+
+/**
+ * An error response from the API, with its status code and decoded body. DeclaredStatus is the response
+ * of the operation matching the status: a status code like '404', a range like '5XX' or 'default'. Operations
+ * declaring error responses export the union of their errors, e.g. ApiError<404, NotFound, '404'> |
+ * ApiError<number, Problem, 'default'>, which narrows by declaredStatus.
+ */
+export class ApiError<S extends number = number, B = unknown, D extends string | undefined = string | undefined> extends Error {
+  readonly status: S;
+  readonly body: B;
+  readonly declaredStatus: D;
+  readonly headers: Record<string, string>;
+  readonly response?: AxiosResponse;
+
+  constructor(status: S, body: B, declaredStatus: D, headers: Record<string, string> = {}, response?: AxiosResponse) {
+    super('Request failed with status code ' + status);
+    this.name = 'ApiError';
+    this.status = status;
+    this.body = body;
+    this.declaredStatus = declaredStatus;
+    this.headers = headers;
+    this.response = response;
+  }
+}
+
+/**
+ * Finds the key matching a status code in a map keyed by status codes, ranges like 4XX, or default.
+ */
+export function statusKey(status: number, entries: Record<string, unknown>): string | undefined {
+  const code = String(status);
+  const range = code.charAt(0) + 'XX';
+  for (const key of [code, range, 'default']) {
+    if (Object.prototype.hasOwnProperty.call(entries, key)) {
+      return key;
+    }
+  }
+  return undefined;
+}
+
+/**
+ * Picks the entry for a status code from a map keyed by status codes, ranges like 4XX, or default.
+ */
+export function statusEntry<T>(status: number, entries: Record<string, T>): T | undefined {
+  const key = statusKey(status, entries);
+  return key === undefined ? undefined : entries[key];
+}
+
+/**
+ * Turns the error of a request that received a response into an ApiError, decoding the body with the
+ * codec shape of the declared error response matching its status. Other errors, like network failures,
+ * are returned as they are.
+ */
+export function toApiError(error: unknown, errors: Record<string, Shape | null>, shapes: Record<string, Shape>): unknown {
+  if (!axios.isAxiosError(error) || !error.response) {
+    return error;
+  }
+
+  const response = error.response;
+  const contentType = String(response.headers['content-type'] || '');
+  let body: unknown = response.data;
+  if (typeof body === 'string' && contentType.indexOf('json') !== -1) {
+    try {
+      body = parseJSON(body);
+    } catch {
+      // keep the body as text
+    }
+  }
+
+  const headers = parseResponseHeaders<Record<string, string>>(response.headers, {});
+  const declaredStatus = statusKey(response.status, errors);
+  const shape = declaredStatus === undefined ? undefined : errors[declaredStatus] || undefined;
+  return new ApiError(response.status, decode(body, shape, shapes), declaredStatus, headers, response);
+}
+
+/**
+ * Describes how a response header is parsed from its text: as a number, boolean or comma-separated array,
+ * optionally converted by a codec.
+ */
+export interface HeaderSpec {
+  type: string;
+  items?: HeaderSpec;
+  codec?: string;
+}
+
+/**
+ * A response with its decoded data, status code and headers.
+ */
+export interface ApiResponse<T, H = Record<string, string>> {
+  data: T;
+  status: number;
+  headers: H;
+}
+
+function parseHeaderValue(text: string, spec: HeaderSpec): unknown {
+  let value: unknown = text;
+  switch (spec.type) {
+    case 'integer':
+    case 'number':
+      value = text.trim() === '' || isNaN(Number(text)) ? text : Number(text);
+      break;
+    case 'boolean':
+      value = text === 'true' ? true : text === 'false' ? false : text;
+      break;
+    case 'array':
+      value = text.split(',').map((item) => parseHeaderValue(item.trim(), spec.items || { type: 'string' }));
+      break;
+  }
+  return spec.codec ? decode(value, spec.codec, {}) : value;
+}
+
+/**
+ * Collects response headers by their lower case names, parsing the headers listed by specs from their text.
+ */
+export function parseResponseHeaders<H>(headers: unknown, specs: Record<string, HeaderSpec>): H {
+  const source = headers && typeof (headers as any).toJSON === 'function' ? (headers as any).toJSON() : headers;
+  const result: Record<string, unknown> = {};
+  if (!isObject(source)) {
+    return result as H;
+  }
+
+  Object.keys(source).forEach((name) => {
+    const value = source[name];
+    if (value === undefined || value === null) {
+      return;
+    }
+    const key = name.toLowerCase();
+    const text = Array.isArray(value) ? value.join(', ') : String(value);
+    result[key] = specs[key] ? parseHeaderValue(text, specs[key]) : text;
+  });
+  return result as H;
+}
+
+export type ResponseFormat = 'json' | 'text' | 'binary';
+
+/**
+ * Returns the request options reading a response as text, or as binary data: a Blob in browsers and an
+ * ArrayBuffer in Node, where Blob responses are not supported.
+ */
+export function responseOptions(format: ResponseFormat): AxiosRequestConfig {
+  switch (format) {
+    case 'text':
+      return { responseType: 'text', transformResponse: [] };
+    case 'binary':
+      return { responseType: isNode ? 'arraybuffer' : 'blob', transformResponse: [] };
+  }
+  return {};
+}
+
+/**
+ * Wraps binary response data in a Blob of the given content type.
+ */
+export function toBlob(data: unknown, contentType?: unknown): Blob {
+  if (data instanceof Blob) {
+    return data;
+  }
+  return new Blob([data as ArrayBuffer], typeof contentType === 'string' ? { type: contentType } : {});
+}
+
+/**
+ * A file downloaded from the API, with the name and content type given by the server.
+ */
+export interface FileDownload {
+  data: Blob;
+  fileName?: string;
+  contentType?: string;
+}
+
+/**
+ * Reads the file name from a Content-Disposition header, preferring the encoded filename* parameter.
+ */
+export function contentDispositionFileName(header: unknown): string | undefined {
+  if (typeof header !== 'string') {
+    return undefined;
+  }
+
+  const encoded = /filename\*\s*=\s*([^']*)'[^']*'([^;]+)/i.exec(header);
+  if (encoded) {
+    try {
+      return decodeURIComponent(encoded[2].trim());
+    } catch {
+      // fall back to the plain file name
+    }
+  }
+
+  const plain = /filename\s*=\s*("((?:[^"\\]|\\.)*)"|[^;]+)/i.exec(header);
+  if (plain) {
+    return plain[2] !== undefined ? plain[2].replace(/\\(.)/g, '$1') : plain[1].trim();
+  }
+  return undefined;
+}
+
+/**
+ * Turns a binary response into a file download.
+ */
+export function toFileDownload(response: AxiosResponse): FileDownload {
+  const contentType = response.headers['content-type'];
+  return {
+    data: toBlob(response.data, contentType),
+    fileName: contentDispositionFileName(response.headers['content-disposition']),
+    contentType: typeof contentType === 'string' ? contentType : undefined,
+  };
+}
+
+/**
+ * Saves a downloaded file in the browser, prompting the user to save it. In Node, write the bytes of
+ * download.data instead, e.g. with Buffer.from(await download.data.arrayBuffer()).
+ */
+export function saveDownload(download: FileDownload, fileName?: string): void {
+  const browser = globalThis as any;
+  if (isNode || !browser.document || !browser.URL) {
+    throw new Error('saveDownload is only supported in browsers');
+  }
+
+  const url = browser.URL.createObjectURL(download.data);
+  const link = browser.document.createElement('a');
+  link.href = url;
+  link.download = fileName || download.fileName || 'download';
+  browser.document.body.appendChild(link);
+  link.click();
+  browser.document.body.removeChild(link);
+  setTimeout(() => browser.URL.revokeObjectURL(url), 0);
+}
+
+/**
+ * Describes a server: its URL, with variables in braces, and the defaults and allowed values of the variables.
+ */
+export interface ServerSpec {
+  url: string;
+  variables?: Record<string, { default: string; enum?: string[] }>;
+}
+
+/**
+ * Substitutes the variables in the URL of a server, using their defaults for the values not given.
+ */
+export function resolveServerURL(server: ServerSpec, values: Record<string, unknown> = {}): string {
+  return server.url.replace(/\{([^}]+)\}/g, (_match: string, name: string) => {
+    const variable = server.variables && server.variables[name];
+    const value = values[name] !== undefined && values[name] !== null ? String(values[name]) : variable && variable.default;
+    if (value === undefined) {
+      throw new Error('Missing value for server variable ' + name);
+    }
+    if (variable && variable.enum && variable.enum.length > 0 && variable.enum.indexOf(value) === -1) {
+      throw new Error('Invalid value ' + JSON.stringify(value) + ' for server variable ' + name + ', expected one of ' + variable.enum.join(', '));
+    }
+    return value;
+  });
+}
+`
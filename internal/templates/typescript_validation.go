@@ -0,0 +1,264 @@
+package templates
+
+// typescriptValidationTemplate holds the validators generated from the constraints of the schemas
+const typescriptValidationTemplate = `// Generated validators for the {{.ProjectName}} client
+import { stringifyJSON } from './runtime';
+
+/**
+ * Describes the constraints a value must satisfy, referencing the rules of the component types by name.
+ */
+export interface Rule {
+  ref?: string;
+  types?: string[];
+  nullable?: boolean;
+  enum?: unknown[];
+  format?: string;
+  minLength?: number;
+  maxLength?: number;
+  pattern?: string;
+  minimum?: number;
+  maximum?: number;
+  exclusiveMinimum?: number;
+  exclusiveMaximum?: number;
+  multipleOf?: number;
+  items?: Rule;
+  minItems?: number;
+  maxItems?: number;
+  uniqueItems?: boolean;
+  properties?: Record<string, Rule>;
+  required?: string[];
+  additionalProperties?: false | Rule;
+  minProperties?: number;
+  maxProperties?: number;
+  allOf?: Rule[];
+  anyOf?: Rule[];
+  discriminator?: string;
+  mapping?: Record<string, Rule>;
+  // codec is "integer-string" for integers generated as strings
+  codec?: string;
+}
+
+export interface ValidationIssue {
+  // path is the location of the invalid value, e.g. "$.items[0].name"
+  path: string;
+  message: string;
+}
+
+/**
+ * Thrown when a value does not satisfy the constraints of its schema.
+ */
+export class ValidationError extends Error {
+  constructor(public readonly issues: ValidationIssue[]) {
+    super(issues.map((issue) => issue.path + ': ' + issue.message).join('; '));
+    this.name = 'ValidationError';
+  }
+}
+
+// rules hold the constraints of the component types
+export const rules: Record<string, Rule> = {
+{{range .Validators}}  {{Quote .Name}}: {{.Rule}},
+{{end}}};
+
+const formats: Record<string, RegExp> = {
+  email: /^[^\s@]+@[^\s@]+\.[^\s@]+$/,
+  uuid: /^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$/i,
+  date: /^\d{4}-\d{2}-\d{2}$/,
+  'date-time': /^\d{4}-\d{2}-\d{2}[Tt ]\d{2}:\d{2}:\d{2}(\.\d+)?([Zz]|[+-]\d{2}:\d{2})$/,
+  uri: /^[a-zA-Z][a-zA-Z0-9+.-]*:\S*$/,
+  ipv4: /^((25[0-5]|2[0-4]\d|1?\d?\d)\.){3}(25[0-5]|2[0-4]\d|1?\d?\d)$/,
+};
+
+// patterns caches the compiled patterns of the rules
+const patterns = new Map<string, RegExp>();
+
+// compilePattern compiles a pattern once, with the u flag so that it matches code points like the patterns
+// of JSON Schema, or without it for patterns only valid without, like those escaping "-" outside a class
+function compilePattern(pattern: string): RegExp {
+  let compiled = patterns.get(pattern);
+  if (compiled === undefined) {
+    try {
+      compiled = new RegExp(pattern, 'u');
+    } catch {
+      compiled = new RegExp(pattern);
+    }
+    patterns.set(pattern, compiled);
+  }
+  return compiled;
+}
+
+function isObject(value: unknown): value is Record<string, unknown> {
+  return typeof value === 'object' && value !== null && !Array.isArray(value) && !(value instanceof Date) && !isBinary(value);
+}
+
+function isBinary(value: unknown): boolean {
+  return (typeof Blob !== 'undefined' && value instanceof Blob) || value instanceof ArrayBuffer || ArrayBuffer.isView(value);
+}
+
+// matchesType reports whether a value is of a schema type, accepting the generated Date, bigint and binary
+// representations; integers generated as strings are converted to bigint before they are checked
+function matchesType(value: unknown, type: string): boolean {
+  switch (type) {
+    case 'string':
+      return typeof value === 'string' || value instanceof Date || isBinary(value);
+    case 'integer':
+      return typeof value === 'bigint' || (typeof value === 'number' && Number.isInteger(value));
+    case 'number':
+      return typeof value === 'bigint' || typeof value === 'number';
+    case 'boolean':
+      return typeof value === 'boolean';
+    case 'array':
+      return Array.isArray(value);
+    case 'object':
+      return isObject(value);
+    case 'null':
+      return value === null;
+  }
+  return true;
+}
+
+function check(value: unknown, rule: Rule, path: string, issues: ValidationIssue[]): void {
+  if (rule.codec === 'integer-string' && typeof value === 'string' && /^-?\d+$/.test(value)) {
+    value = BigInt(value);
+  }
+  if (value === null) {
+    if (rule.nullable || (rule.types && rule.types.indexOf('null') !== -1) || (rule.enum && rule.enum.indexOf(null) !== -1)) {
+      return;
+    }
+    if (rule.ref) {
+      check(value, rules[rule.ref] || {}, path, issues);
+    } else if (rule.types || rule.enum) {
+      issues.push({ path, message: 'must not be null' });
+    }
+    return;
+  }
+  if (rule.ref) {
+    check(value, rules[rule.ref] || {}, path, issues);
+    return;
+  }
+
+  if (rule.types && !rule.types.some((type) => matchesType(value, type))) {
+    issues.push({ path, message: 'must be of type ' + rule.types.join(' or ') });
+    return;
+  }
+  if (rule.enum && !rule.enum.some((member) => member === value || (typeof value === 'bigint' && member === Number(value)))) {
+    issues.push({ path, message: 'must be one of ' + rule.enum.map((member) => JSON.stringify(member)).join(', ') });
+  }
+
+  if (typeof value === 'string') {
+    if (rule.minLength !== undefined && value.length < rule.minLength) {
+      issues.push({ path, message: 'must be at least ' + rule.minLength + ' characters long' });
+    }
+    if (rule.maxLength !== undefined && value.length > rule.maxLength) {
+      issues.push({ path, message: 'must be at most ' + rule.maxLength + ' characters long' });
+    }
+    if (rule.pattern !== undefined && !compilePattern(rule.pattern).test(value)) {
+      issues.push({ path, message: 'must match the pattern ' + rule.pattern });
+    }
+    if (rule.format && formats[rule.format] && !formats[rule.format].test(value)) {
+      issues.push({ path, message: 'must be a valid ' + rule.format });
+    }
+  }
+
+  if (typeof value === 'number' || typeof value === 'bigint') {
+    const number = Number(value);
+    if (rule.minimum !== undefined && number < rule.minimum) {
+      issues.push({ path, message: 'must be at least ' + rule.minimum });
+    }
+    if (rule.maximum !== undefined && number > rule.maximum) {
+      issues.push({ path, message: 'must be at most ' + rule.maximum });
+    }
+    if (rule.exclusiveMinimum !== undefined && number <= rule.exclusiveMinimum) {
+      issues.push({ path, message: 'must be greater than ' + rule.exclusiveMinimum });
+    }
+    if (rule.exclusiveMaximum !== undefined && number >= rule.exclusiveMaximum) {
+      issues.push({ path, message: 'must be less than ' + rule.exclusiveMaximum });
+    }
+    if (rule.multipleOf !== undefined) {
+      const quotient = number / rule.multipleOf;
+      if (Math.abs(quotient - Math.round(quotient)) > 1e-9) {
+        issues.push({ path, message: 'must be a multiple of ' + rule.multipleOf });
+      }
+    }
+  }
+
+  if (Array.isArray(value)) {
+    if (rule.minItems !== undefined && value.length < rule.minItems) {
+      issues.push({ path, message: 'must have at least ' + rule.minItems + ' items' });
+    }
+    if (rule.maxItems !== undefined && value.length > rule.maxItems) {
+      issues.push({ path, message: 'must have at most ' + rule.maxItems + ' items' });
+    }
+    if (rule.uniqueItems) {
+      const seen = value.map((item) => stringifyJSON(item));
+      if (seen.some((item, index) => seen.indexOf(item) !== index)) {
+        issues.push({ path, message: 'must not contain duplicate items' });
+      }
+    }
+    if (rule.items) {
+      value.forEach((item, index) => check(item, rule.items!, path + '[' + index + ']', issues));
+    }
+  }
+
+  if (isObject(value)) {
+    const keys = Object.keys(value).filter((key) => value[key] !== undefined);
+    if (rule.minProperties !== undefined && keys.length < rule.minProperties) {
+      issues.push({ path, message: 'must have at least ' + rule.minProperties + ' properties' });
+    }
+    if (rule.maxProperties !== undefined && keys.length > rule.maxProperties) {
+      issues.push({ path, message: 'must have at most ' + rule.maxProperties + ' properties' });
+    }
+    (rule.required || []).forEach((key) => {
+      if (value[key] === undefined) {
+        issues.push({ path: path + '.' + key, message: 'is required' });
+      }
+    });
+    keys.forEach((key) => {
+      const property = rule.properties && rule.properties[key];
+      if (property) {
+        check(value[key], property, path + '.' + key, issues);
+      } else if (rule.additionalProperties === false) {
+        issues.push({ path: path + '.' + key, message: 'is not allowed' });
+      } else if (rule.additionalProperties) {
+        check(value[key], rule.additionalProperties, path + '.' + key, issues);
+      }
+    });
+
+    if (rule.discriminator && rule.mapping) {
+      const variant = rule.mapping[String(value[rule.discriminator])];
+      if (variant) {
+        check(value, variant, path, issues);
+      } else {
+        issues.push({ path: path + '.' + rule.discriminator, message: 'must be one of ' + Object.keys(rule.mapping).join(', ') });
+      }
+    }
+  }
+
+  (rule.allOf || []).forEach((part) => check(value, part, path, issues));
+  if (rule.anyOf && !rule.anyOf.some((variant) => validate(value, variant).length === 0)) {
+    issues.push({ path, message: 'must match one of the allowed variants' });
+  }
+}
+
+/**
+ * Checks a value against a rule, returning the constraints it does not satisfy.
+ */
+export function validate(value: unknown, rule: Rule): ValidationIssue[] {
+  const issues: ValidationIssue[] = [];
+  check(value, rule, '$', issues);
+  return issues;
+}
+
+/**
+ * Checks a value against a rule, throwing a ValidationError when it does not satisfy the constraints.
+ */
+export function assertValid(value: unknown, rule: Rule): void {
+  const issues = validate(value, rule);
+  if (issues.length > 0) {
+    throw new ValidationError(issues);
+  }
+}
+{{range .Validators}}
+export function validate{{.TypeName}}(value: unknown): ValidationIssue[] {
+  return validate(value, { ref: {{Quote .Name}} });
+}
+{{end}}`
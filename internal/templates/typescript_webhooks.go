@@ -0,0 +1,169 @@
+package templates
+
+// typescriptWebhooksTemplate holds the payload types and handlers of the webhooks and callbacks the API sends
+const typescriptWebhooksTemplate = `// Generated webhook and callback handlers for the {{.ProjectName}} API
+import { {{$sep := ""}}{{range .Types}}{{$sep}}{{.Name}}{{$sep = ", "}}{{end}}{{range .BrandedTypes}}{{$sep}}{{.Name}}{{$sep = ", "}}{{end}} } from './types';
+import { decode, toBlob, {{if .LosslessIntegers}}parseJSON, {{end}}Shape } from './runtime';
+import { shapes } from './client';
+
+export type WebhookHeaders = Record<string, string | string[] | undefined>;
+
+/**
+ * A request the API sent to your server, with its payload decoded.
+ */
+export interface WebhookRequest<T> {
+  payload: T;
+  headers: WebhookHeaders;
+}
+
+/**
+ * Handles a webhook or callback, answering with the response the API expects.
+ */
+export type WebhookHandler<T, R = void> = (request: WebhookRequest<T>) => R | Promise<R>;
+
+/**
+ * Checks the signature of a request against its raw body before the payload is decoded, e.g. an HMAC of
+ * the body with a shared secret.
+ */
+export type SignatureVerifier = (rawBody: string | Uint8Array, headers: WebhookHeaders) => boolean | Promise<boolean>;
+
+/**
+ * Thrown when the signature of a request does not verify.
+ */
+export class WebhookSignatureError extends Error {
+  constructor() {
+    super('Invalid webhook signature');
+    this.name = 'WebhookSignatureError';
+  }
+}
+{{range .Webhooks}}
+{{JSDoc "" .Doc}}export type {{.TypeName}}Payload = {{.PayloadType}};
+{{if .Operation}}
+/**
+ * Handles the {{Quote .Key}} callback of {{.Operation}}, sent with {{.HTTPMethod}} to {{Comment .Expression}}{{if .Doc.Deprecated}}
+ * @deprecated{{end}}
+ */{{else}}
+/**
+ * Handles the {{Quote .Key}} webhook, sent with {{.HTTPMethod}}{{if .Doc.Deprecated}}
+ * @deprecated{{end}}
+ */{{end}}
+export type {{.TypeName}}Handler = WebhookHandler<{{.TypeName}}Payload, {{.ResponseType}}>;
+{{end}}
+/**
+ * The payload and response types of the webhooks and callbacks, by name
+ */
+export interface {{.ClientClassName}}Webhooks {
+{{range .Webhooks}}  {{.Name}}: { payload: {{.TypeName}}Payload; response: {{.ResponseType}} };
+{{end}}}
+
+export type {{.ClientClassName}}WebhookHandlers = {
+  [K in keyof {{.ClientClassName}}Webhooks]?: WebhookHandler<{{.ClientClassName}}Webhooks[K]['payload'], {{.ClientClassName}}Webhooks[K]['response']>;
+};
+
+interface WebhookSpec {
+  method: string;
+  format?: string;
+  shape?: Shape;
+}
+
+// webhookSpecs tell how the payloads of the webhooks and callbacks are read
+const webhookSpecs: Record<keyof {{.ClientClassName}}Webhooks, WebhookSpec> = {
+{{range .Webhooks}}  {{.Name}}: { method: '{{.HTTPMethod}}'{{if .PayloadFormat}}, format: '{{.PayloadFormat}}'{{end}}{{if .PayloadShape}}, shape: {{.PayloadShape}}{{end}} },
+{{end}}};
+
+function headerValue(headers: WebhookHeaders, name: string): string | undefined {
+  const key = Object.keys(headers).find((header) => header.toLowerCase() === name);
+  const value = key === undefined ? undefined : headers[key];
+  return Array.isArray(value) ? value[0] : value;
+}
+
+function bodyText(rawBody: string | Uint8Array): string {
+  return typeof rawBody === 'string' ? rawBody : new (globalThis as any).TextDecoder().decode(rawBody);
+}
+
+/**
+ * Decodes the payload of a webhook or callback from the raw body of the request.
+ */
+export function parseWebhookPayload<K extends keyof {{.ClientClassName}}Webhooks>(
+  name: K,
+  rawBody: string | Uint8Array,
+  headers: WebhookHeaders = {}
+): {{.ClientClassName}}Webhooks[K]['payload'] {
+  const spec = webhookSpecs[name];
+  switch (spec.format) {
+    case 'json':
+      return decode(bodyText(rawBody) === '' ? undefined : {{if .LosslessIntegers}}parseJSON{{else}}JSON.parse{{end}}(bodyText(rawBody)), spec.shape, shapes);
+    case 'text':
+      return bodyText(rawBody) as {{.ClientClassName}}Webhooks[K]['payload'];
+    case 'binary':
+      return toBlob(rawBody, headerValue(headers, 'content-type')) as {{.ClientClassName}}Webhooks[K]['payload'];
+  }
+  return undefined as {{.ClientClassName}}Webhooks[K]['payload'];
+}
+
+/**
+ * Verifies and decodes a request the API sent to your server, and passes it to the handler of the webhook
+ * or callback. Wire it into any framework by giving the raw body and headers of the request, and send the
+ * result back as the response.
+ */
+export async function handleWebhook<K extends keyof {{.ClientClassName}}Webhooks>(
+  name: K,
+  request: { rawBody: string | Uint8Array; headers: WebhookHeaders },
+  handlers: {{.ClientClassName}}WebhookHandlers,
+  options: { verify?: SignatureVerifier } = {}
+): Promise<{{.ClientClassName}}Webhooks[K]['response']> {
+  const handler = handlers[name] as WebhookHandler<unknown, {{.ClientClassName}}Webhooks[K]['response']> | undefined;
+  if (!handler) {
+    throw new Error('No handler for the ' + String(name) + ' webhook');
+  }
+  if (options.verify && !(await options.verify(request.rawBody, request.headers))) {
+    throw new WebhookSignatureError();
+  }
+  return handler({ payload: parseWebhookPayload(name, request.rawBody, request.headers), headers: request.headers });
+}
+
+export interface HmacVerifierOptions {
+  secret: string;
+  // header is the request header holding the signature
+  header: string;
+  // algorithm is the hash of the HMAC, SHA-256 by default
+  algorithm?: 'SHA-1' | 'SHA-256' | 'SHA-512';
+  // encoding of the signature, hex by default
+  encoding?: 'hex' | 'base64';
+  // prefix before the signature in the header, e.g. "sha256="
+  prefix?: string;
+}
+
+/**
+ * Creates a verifier checking an HMAC of the raw body against a signature header, using the Web Crypto API
+ * of browsers and Node 19 or later.
+ */
+export function createHmacVerifier(options: HmacVerifierOptions): SignatureVerifier {
+  const { secret, header, algorithm = 'SHA-256', encoding = 'hex', prefix = '' } = options;
+  const crypto = (globalThis as any).crypto;
+  const encoder = new (globalThis as any).TextEncoder();
+
+  return async (rawBody, headers) => {
+    const signature = headerValue(headers, header.toLowerCase());
+    if (!signature || !signature.startsWith(prefix)) {
+      return false;
+    }
+
+    const key = await crypto.subtle.importKey('raw', encoder.encode(secret), { name: 'HMAC', hash: algorithm }, false, ['sign']);
+    const body = typeof rawBody === 'string' ? encoder.encode(rawBody) : rawBody;
+    const bytes: number[] = Array.from(new Uint8Array(await crypto.subtle.sign('HMAC', key, body)));
+    const expected =
+      encoding === 'hex'
+        ? bytes.map((byte) => byte.toString(16).padStart(2, '0')).join('')
+        : (globalThis as any).btoa(String.fromCharCode(...bytes));
+
+    // compare every character so the time taken does not reveal where the signatures differ
+    const actual = encoding === 'hex' ? signature.slice(prefix.length).toLowerCase() : signature.slice(prefix.length);
+    let difference = actual.length ^ expected.length;
+    for (let i = 0; i < expected.length; i++) {
+      difference |= expected.charCodeAt(i) ^ actual.charCodeAt(i % Math.max(actual.length, 1));
+    }
+    return difference === 0;
+  };
+}
+`
@@ -3,31 +3,69 @@ package utils
 import (
 	"regexp"
 	"strings"
+	"unicode"
 )
 
+// SplitWords splits a string into words at separators and case changes,
+// e.g. "inProgress", "in-progress" and "IN_PROGRESS" all become "in"/"IN" and "Progress"/"progress"/"PROGRESS"
+func SplitWords(s string) []string {
+	var words []string
+	runes := []rune(s)
+	start := -1
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				words = append(words, string(runes[start:i]))
+				start = -1
+			}
+			continue
+		}
+
+		if start >= 0 {
+			prev := runes[i-1]
+			boundary := ((unicode.IsLower(prev) || unicode.IsDigit(prev)) && unicode.IsUpper(r)) ||
+				// the last capital of an acronym starts the next word, as in "HTTPServer"
+				(unicode.IsUpper(prev) && unicode.IsUpper(r) && i+1 < len(runes) && unicode.IsLower(runes[i+1]))
+			if boundary {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		} else {
+			start = i
+		}
+	}
+
+	if start >= 0 {
+		words = append(words, string(runes[start:]))
+	}
+
+	return words
+}
+
 // ToPascalCase converts a string to PascalCase
 func ToPascalCase(s string) string {
-	words := regexp.MustCompile(`[^a-zA-Z0-9]+`).Split(s, -1)
+	words := SplitWords(s)
 	for i, word := range words {
-		if len(word) > 0 {
-			words[i] = strings.ToUpper(word[:1]) + strings.ToLower(word[1:])
-		}
+		runes := []rune(strings.ToLower(word))
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
 	}
 	return strings.Join(words, "")
 }
 
 // ToCamelCase converts a string to camelCase
 func ToCamelCase(s string) string {
-	pascal := ToPascalCase(s)
-	if len(pascal) > 0 {
-		return strings.ToLower(pascal[:1]) + pascal[1:]
+	runes := []rune(ToPascalCase(s))
+	if len(runes) > 0 {
+		runes[0] = unicode.ToLower(runes[0])
 	}
-	return pascal
+	return string(runes)
 }
 
 // ToSnakeCase converts a string to snake_case
 func ToSnakeCase(s string) string {
-	words := regexp.MustCompile(`[^a-zA-Z0-9]+`).Split(s, -1)
+	words := SplitWords(s)
 	for i, word := range words {
 		words[i] = strings.ToLower(word)
 	}
@@ -43,3 +81,49 @@ func Contains(slice []string, item string) bool {
 	}
 	return false
 }
+
+// IsIdentifier checks if a string is a valid ASCII identifier (letters, digits, _ and $, not starting with a digit)
+func IsIdentifier(s string) bool {
+	return identifierPattern.MatchString(s)
+}
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// QuoteString renders a string as a single-quoted string literal, escaping quotes, backslashes and line breaks
+func QuoteString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\r", `\r`).Replace(s) + "'"
+}
+
+// InlineComment flattens text onto a single line that cannot terminate the comment it is placed in
+func InlineComment(text string) string {
+	return strings.ReplaceAll(strings.Join(strings.Fields(text), " "), "*/", "*\\/")
+}
+
+// ToIdentifier converts an arbitrary string to a camelCase identifier, keeping the casing inside words
+func ToIdentifier(s string) string {
+	words := regexp.MustCompile(`[^a-zA-Z0-9]+`).Split(s, -1)
+
+	var b strings.Builder
+	for _, word := range words {
+		if word == "" {
+			continue
+		}
+		if b.Len() == 0 {
+			b.WriteString(strings.ToLower(word[:1]) + word[1:])
+		} else {
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+
+	identifier := b.String()
+	if identifier == "" || (identifier[0] >= '0' && identifier[0] <= '9') {
+		identifier = "_" + identifier
+	}
+	return identifier
+}
+
+// ToPascalIdentifier converts an arbitrary string to a PascalCase identifier, keeping the casing inside words
+func ToPascalIdentifier(s string) string {
+	identifier := ToIdentifier(s)
+	return strings.ToUpper(identifier[:1]) + identifier[1:]
+}
@@ -12,12 +12,17 @@ import (
 
 func main() {
 	var (
-		specPath     = flag.String("spec", "", "Path to OpenAPI spec file")
-		projectName  = flag.String("name", "", "Project name for the client")
-		outputDir    = flag.String("output", "./generated-client", "Output directory")
-		language     = flag.String("lang", "typescript", "Target language (typescript, python)")
-		templatesDir = flag.String("templates", "", "Custom templates directory")
-		prettier     = flag.Bool("prettier", true, "Run prettier after generation")
+		specPath          = flag.String("spec", "", "Path to OpenAPI spec file")
+		projectName       = flag.String("name", "", "Project name for the client")
+		outputDir         = flag.String("output", "./generated-client", "Output directory")
+		language          = flag.String("lang", "typescript", "Target language (typescript, python)")
+		templatesDir      = flag.String("templates", "", "Custom templates directory")
+		prettier          = flag.Bool("prettier", true, "Run prettier after generation")
+		enumStyle         = flag.String("enum-style", "union", "How to generate enums (union, const, enum)")
+		typeMappings      = flag.String("type-mappings", "", "JSON file mapping schema formats to types, per language")
+		responseMode      = flag.String("response-mode", "data", "What methods return (data, envelope, both)")
+		validation        = flag.Bool("validation", false, "Generate validators from schema constraints")
+		excludeDeprecated = flag.Bool("exclude-deprecated", false, "Leave deprecated operations out of the client")
 	)
 	flag.Parse()
 
@@ -31,6 +36,11 @@ func main() {
 		WithOutputDir(*outputDir).
 		WithLanguage(*language).
 		WithTemplatesDir(*templatesDir).
+		WithEnumStyle(*enumStyle).
+		WithTypeMappings(*typeMappings).
+		WithResponseMode(*responseMode).
+		WithValidation(*validation).
+		WithExcludeDeprecated(*excludeDeprecated).
 		Build()
 
 	if err := generator.Generate(); err != nil {
@@ -56,4 +66,5 @@ func main() {
 	}
 
 	fmt.Printf("%s client generated successfully in %s\n", strings.Title(*language), *outputDir)
+	fmt.Println(generator.Summary())
 }